@@ -0,0 +1,131 @@
+package gandi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-gandi/go-gandi/config"
+	"github.com/go-gandi/go-gandi/types"
+)
+
+// apiClient sends requests to the endpoints of the Gandi API which are
+// not covered by go-gandi. It behaves like the go-gandi clients: it
+// uses the same credentials and options, and failed requests return a
+// *types.RequestError. Unlike them, its requests are bound to a
+// context.
+type apiClient struct {
+	endpoint  string
+	apiKey    string
+	pat       string
+	sharingID string
+	dryRun    bool
+	client    *http.Client
+}
+
+// newAPIClient returns a client sending its requests with the
+// transport, http.DefaultTransport if nil. The timeout covers all the
+// attempts of the transport.
+func newAPIClient(c config.Config, transport http.RoundTripper) *apiClient {
+	url := c.APIURL
+	if url == "" {
+		url = config.APIURL
+	}
+	return &apiClient{
+		endpoint:  strings.TrimSuffix(url, "/") + "/v5/",
+		apiKey:    c.APIKey,
+		pat:       c.PersonalAccessToken,
+		sharingID: c.SharingID,
+		dryRun:    c.DryRun,
+		client:    &http.Client{Transport: transport, Timeout: requestTimeout},
+	}
+}
+
+// Get issues a GET request on path, relative to the v5 API, and
+// decodes the JSON response into recipient.
+func (c *apiClient) Get(ctx context.Context, path string, recipient interface{}) error {
+	return c.do(ctx, http.MethodGet, path, nil, recipient)
+}
+
+// Post issues a POST request with params encoded as JSON
+func (c *apiClient) Post(ctx context.Context, path string, params, recipient interface{}) error {
+	return c.do(ctx, http.MethodPost, path, params, recipient)
+}
+
+// Put issues a PUT request with params encoded as JSON
+func (c *apiClient) Put(ctx context.Context, path string, params, recipient interface{}) error {
+	return c.do(ctx, http.MethodPut, path, params, recipient)
+}
+
+// Patch issues a PATCH request with params encoded as JSON
+func (c *apiClient) Patch(ctx context.Context, path string, params, recipient interface{}) error {
+	return c.do(ctx, http.MethodPatch, path, params, recipient)
+}
+
+// Delete issues a DELETE request
+func (c *apiClient) Delete(ctx context.Context, path string, recipient interface{}) error {
+	return c.do(ctx, http.MethodDelete, path, nil, recipient)
+}
+
+func (c *apiClient) do(ctx context.Context, method, path string, params, recipient interface{}) error {
+	var body io.Reader
+	if params != nil {
+		encoded, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("failed to encode the request parameters: %w", err)
+		}
+		body = bytes.NewReader(encoded)
+	}
+	url := c.endpoint + path
+	if c.sharingID != "" {
+		separator := "?"
+		if strings.Contains(path, "?") {
+			separator = "&"
+		}
+		url += separator + "sharing_id=" + c.sharingID
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return fmt.Errorf("failed to create the request: %w", err)
+	}
+	if c.pat != "" {
+		req.Header.Add("Authorization", "Bearer "+c.pat)
+	} else {
+		req.Header.Add("Authorization", "Apikey "+c.apiKey)
+	}
+	req.Header.Add("Content-Type", "application/json")
+	if c.dryRun {
+		req.Header.Add("Dry-Run", "1")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to do the request: %w", err)
+	}
+	defer resp.Body.Close()
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read the response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// The error is described by a message or a list of field errors
+		var response types.StandardResponse
+		_ = json.Unmarshal(content, &response)
+		return &types.RequestError{
+			Err:        &apiResponseError{StatusCode: resp.StatusCode, Response: response},
+			StatusCode: resp.StatusCode,
+		}
+	}
+	if recipient == nil || resp.StatusCode == http.StatusNoContent || len(content) == 0 {
+		return nil
+	}
+	if err = json.Unmarshal(content, recipient); err != nil {
+		return fmt.Errorf("failed to decode the response: %w", err)
+	}
+	return nil
+}
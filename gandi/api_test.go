@@ -0,0 +1,38 @@
+package gandi
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-gandi/go-gandi/config"
+	"github.com/go-gandi/go-gandi/types"
+)
+
+func TestAPIClient(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addZone("example.com")
+	client := newAPIClient(config.Config{APIURL: api.server.URL, PersonalAccessToken: "token", SharingID: "org"}, nil)
+
+	var response struct {
+		ID string `json:"id"`
+	}
+	if err := client.Post(context.Background(), "livedns/domains/example.com/snapshots", map[string]string{"name": "test"}, &response); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if response.ID == "" {
+		t.Fatalf("the response should have been decoded")
+	}
+
+	err := client.Get(context.Background(), "livedns/domains/unknown.com/snapshots", nil)
+	requestError, ok := err.(*types.RequestError)
+	if !ok || requestError.StatusCode != 404 {
+		t.Fatalf("expected a 404 request error, got %#v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err = client.Get(ctx, "livedns/domains/example.com/snapshots", nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the request to be canceled, got %v", err)
+	}
+}
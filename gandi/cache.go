@@ -0,0 +1,237 @@
+package gandi
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/go-gandi/go-gandi/domain"
+	"github.com/go-gandi/go-gandi/livedns"
+)
+
+// readCache shares the reads of the resources and data sources during
+// a Terraform run, which configures its own provider. When refreshing
+// a zone, the records of the zone are fetched once and the records are
+// then served from this list instead of being fetched one by one. The
+// domains are cached in the same way, with their LiveDNS status and
+// their tags and their owner change.
+//
+// Concurrent reads of the same entry wait for a single request. Once
+// the provider writes a zone or a domain, its entries are no longer
+// cached for the rest of the run: a resource reading its record after
+// writing it would otherwise fetch the whole zone each time.
+type readCache struct {
+	liveDNS *livedns.LiveDNS
+	domain  *domain.Domain
+	api     *apiClient
+	// transport sends the requests of go-gandi
+	transport *providerTransport
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	written map[string]bool
+}
+
+// cacheEntry is a cached value, available once done is closed
+type cacheEntry struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+func newReadCache(liveDNS *livedns.LiveDNS, domain *domain.Domain, api *apiClient, transport *providerTransport) *readCache {
+	return &readCache{
+		liveDNS:   liveDNS,
+		domain:    domain,
+		api:       api,
+		transport: transport,
+		entries:   map[string]*cacheEntry{},
+		written:   map[string]bool{},
+	}
+}
+
+// load returns the value of the entry, fetching it if it isn't cached.
+// Errors are not cached: the next load fetches the value again. A read
+// waiting for the fetch of another one stops waiting when its own ctx
+// is done, and fetches the value again if the other read was canceled.
+func (c *readCache) load(ctx context.Context, key string, fetch func() (interface{}, error)) (interface{}, error) {
+	for {
+		c.mu.Lock()
+		if c.written[key] {
+			c.mu.Unlock()
+			return fetch()
+		}
+		entry, ok := c.entries[key]
+		if !ok {
+			entry = &cacheEntry{done: make(chan struct{})}
+			c.entries[key] = entry
+		}
+		c.mu.Unlock()
+
+		if !ok {
+			return c.fetch(key, entry, fetch)
+		}
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if errors.Is(entry.err, context.Canceled) || errors.Is(entry.err, context.DeadlineExceeded) {
+			continue
+		}
+		return entry.value, entry.err
+	}
+}
+
+// fetch fills the entry, which is no longer cached if it failed
+func (c *readCache) fetch(key string, entry *cacheEntry, fetch func() (interface{}, error)) (interface{}, error) {
+	entry.value, entry.err = fetch()
+	if entry.err != nil {
+		c.mu.Lock()
+		if c.entries[key] == entry {
+			delete(c.entries, key)
+		}
+		c.mu.Unlock()
+	}
+	close(entry.done)
+	return entry.value, entry.err
+}
+
+// invalidate stops caching the entries. It is called before writing
+// them, so that the reads started after the write aren't served from a
+// value fetched before.
+func (c *readCache) invalidate(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.entries, key)
+		c.written[key] = true
+	}
+}
+
+// zoneRecords returns all the records of a zone. The returned records
+// can be modified by the caller.
+func (c *readCache) zoneRecords(ctx context.Context, zone string) ([]livedns.DomainRecord, error) {
+	value, err := c.load(ctx, "records/"+zone, func() (interface{}, error) {
+		var records []livedns.DomainRecord
+		err := c.transport.call(ctx, true, func() (err error) {
+			records, err = c.liveDNS.GetDomainRecords(zone)
+			return
+		})
+		return records, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	records := value.([]livedns.DomainRecord)
+	copied := make([]livedns.DomainRecord, len(records))
+	for i, r := range records {
+		r.RrsetValues = append([]string(nil), r.RrsetValues...)
+		copied[i] = r
+	}
+	return copied, nil
+}
+
+// zoneRecord returns a record of a zone, as
+// GetDomainRecordByNameAndType. It is served from the records of the
+// zone unless the zone has been written during the run. A record
+// missing from the zone is fetched anyway, to return the same error as
+// the API.
+func (c *readCache) zoneRecord(ctx context.Context, zone, name, recordType string) (livedns.DomainRecord, error) {
+	c.mu.Lock()
+	written := c.written["records/"+zone]
+	c.mu.Unlock()
+	if written {
+		return c.getRecord(ctx, zone, name, recordType)
+	}
+
+	records, err := c.zoneRecords(ctx, zone)
+	if err != nil {
+		return livedns.DomainRecord{}, err
+	}
+	for _, r := range records {
+		if strings.EqualFold(r.RrsetName, name) && r.RrsetType == recordType {
+			return r, nil
+		}
+	}
+	return c.getRecord(ctx, zone, name, recordType)
+}
+
+func (c *readCache) getRecord(ctx context.Context, zone, name, recordType string) (record livedns.DomainRecord, err error) {
+	err = c.transport.call(ctx, true, func() (err error) {
+		record, err = c.liveDNS.GetDomainRecordByNameAndType(zone, name, recordType)
+		return
+	})
+	return
+}
+
+// invalidateZone must be called before writing the records of a zone
+func (c *readCache) invalidateZone(zone string) {
+	c.invalidate("records/" + zone)
+}
+
+// domainDetails returns the details of a domain, as GetDomain
+func (c *readCache) domainDetails(ctx context.Context, fqdn string) (domain.Details, error) {
+	value, err := c.load(ctx, "domain/"+fqdn, func() (interface{}, error) {
+		var details domain.Details
+		err := c.transport.call(ctx, true, func() (err error) {
+			details, err = c.domain.GetDomain(fqdn)
+			return
+		})
+		return details, err
+	})
+	if err != nil {
+		return domain.Details{}, err
+	}
+	return value.(domain.Details), nil
+}
+
+// domainLiveDNS returns the LiveDNS status of a domain, as GetLiveDNS
+func (c *readCache) domainLiveDNS(ctx context.Context, fqdn string) (domain.LiveDNS, error) {
+	value, err := c.load(ctx, "livedns/"+fqdn, func() (interface{}, error) {
+		var liveDNS domain.LiveDNS
+		err := c.transport.call(ctx, true, func() (err error) {
+			liveDNS, err = c.domain.GetLiveDNS(fqdn)
+			return
+		})
+		return liveDNS, err
+	})
+	if err != nil {
+		return domain.LiveDNS{}, err
+	}
+	return value.(domain.LiveDNS), nil
+}
+
+// domainTags returns the tags of a domain, as GetTags
+func (c *readCache) domainTags(ctx context.Context, fqdn string) ([]string, error) {
+	value, err := c.load(ctx, "tags/"+fqdn, func() (interface{}, error) {
+		var tags []string
+		err := c.transport.call(ctx, true, func() (err error) {
+			tags, err = c.domain.GetTags(fqdn)
+			return
+		})
+		return tags, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]string(nil), value.([]string)...), nil
+}
+
+// domainOwnerChange returns the last owner change of a domain, as
+// getOwnerChange
+func (c *readCache) domainOwnerChange(ctx context.Context, fqdn string) (*ownerChange, error) {
+	value, err := c.load(ctx, "changeowner/"+fqdn, func() (interface{}, error) {
+		return getOwnerChange(ctx, c.api, fqdn)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*ownerChange), nil
+}
+
+// invalidateDomain must be called before writing a domain
+func (c *readCache) invalidateDomain(fqdn string) {
+	c.invalidate("domain/"+fqdn, "livedns/"+fqdn, "tags/"+fqdn, "changeowner/"+fqdn)
+}
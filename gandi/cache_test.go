@@ -0,0 +1,177 @@
+package gandi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-gandi/go-gandi/livedns"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// exactRequestCount returns the number of requests received so far
+// whose "METHOD path" description is request.
+func (api *fakeGandiAPI) exactRequestCount(request string) int {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	count := 0
+	for _, r := range api.requests {
+		if r == request {
+			count++
+		}
+	}
+	return count
+}
+
+func TestOfflineReadCache_records(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	var records []livedns.DomainRecord
+	for i := 0; i < 20; i++ {
+		records = append(records, livedns.DomainRecord{
+			RrsetName:   fmt.Sprintf("www%d", i),
+			RrsetType:   "A",
+			RrsetTTL:    300,
+			RrsetValues: []string{fmt.Sprintf("192.0.2.%d", i)},
+		})
+	}
+	api.addZone("example.com", records...)
+
+	// The records are refreshed concurrently, as Terraform does
+	p := newTestOfflineProvider(t, api)
+	states := make([]*terraform.InstanceState, len(records))
+	diags := make([]diag.Diagnostics, len(records))
+	var wg sync.WaitGroup
+	for i := range records {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			states[i], diags[i] = p.resource("gandi_livedns_record").RefreshWithoutUpgrade(context.Background(), &terraform.InstanceState{
+				ID: fmt.Sprintf("example.com/www%d/A", i),
+			}, p.provider.Meta())
+		}()
+	}
+	wg.Wait()
+
+	for i, state := range states {
+		if diags[i].HasError() {
+			t.Fatalf("failed to refresh the record www%d: %v", i, diags[i])
+		}
+		testCheckAttributes(t, state, map[string]string{
+			"name":     fmt.Sprintf("www%d", i),
+			"values.#": "1",
+		})
+	}
+	if count := api.exactRequestCount("GET livedns/domains/example.com/records"); count != 1 {
+		t.Fatalf("expected the records of the zone to be fetched once, got %d requests", count)
+	}
+	if count := api.requestCount("GET livedns/domains/example.com/records/"); count != 0 {
+		t.Fatalf("expected the records to be served from the zone records, got %d requests", count)
+	}
+
+	// Once the zone is written, the records are read again from the API
+	api.setRecord("example.com", livedns.DomainRecord{RrsetName: "www1", RrsetType: "A", RrsetTTL: 300, RrsetValues: []string{"198.51.100.1"}})
+	p.apply("gandi_livedns_record", states[0], map[string]interface{}{
+		"zone":   "example.com",
+		"name":   "www0",
+		"type":   "A",
+		"ttl":    600,
+		"values": []interface{}{"192.0.2.0"},
+	})
+	state := p.refresh("gandi_livedns_record", states[1])
+	values := p.resource("gandi_livedns_record").Data(state).Get("values").(*schema.Set)
+	if values.Len() != 1 || !values.Contains("198.51.100.1") {
+		t.Fatalf("expected the record changed outside Terraform to be read again, got %v", values.List())
+	}
+}
+
+func TestOfflineReadCache_missingRecord(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addZone("example.com", livedns.DomainRecord{RrsetName: "www", RrsetType: "A", RrsetTTL: 300, RrsetValues: []string{"192.0.2.1"}})
+
+	p := newTestOfflineProvider(t, api)
+	state := p.refresh("gandi_livedns_record", &terraform.InstanceState{ID: "example.com/api/A"})
+	if state != nil {
+		t.Fatalf("expected the missing record to be removed from the state, got %v", state)
+	}
+}
+
+func TestOfflineReadCache_domain(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	config := testOfflineDomainConfig(map[string]interface{}{"autorenew": true})
+	state := newTestOfflineProvider(t, api).apply("gandi_domain", nil, config)
+
+	p := newTestOfflineProvider(t, api)
+	api.requests = nil
+	state = p.refresh("gandi_domain", state)
+	p.readDataSource("gandi_domain", map[string]interface{}{"name": "example.com"})
+	for _, request := range []string{
+		"GET domain/domains/example.com",
+		"GET domain/domains/example.com/livedns",
+		"GET domain/domains/example.com/tags",
+	} {
+		if count := api.exactRequestCount(request); count != 1 {
+			t.Errorf("expected a single %s request, got %d", request, count)
+		}
+	}
+
+	config["autorenew"] = false
+	state = p.apply("gandi_domain", state, config)
+	testCheckAttributes(t, state, map[string]string{"autorenew": "false"})
+	if count := api.exactRequestCount("GET domain/domains/example.com"); count != 2 {
+		t.Fatalf("expected the domain to be read again once written, got %d requests", count)
+	}
+}
+
+func TestReadCache_canceledFetch(t *testing.T) {
+	cache := newReadCache(nil, nil, nil, nil)
+	fetching := make(chan struct{})
+	canceled := make(chan struct{})
+	go func() {
+		_, _ = cache.load(context.Background(), "key", func() (interface{}, error) {
+			close(fetching)
+			<-canceled
+			return nil, context.Canceled
+		})
+	}()
+	<-fetching
+
+	// A read waiting for a fetch canceled by another operation fetches
+	// the value again
+	go close(canceled)
+	value, err := cache.load(context.Background(), "key", func() (interface{}, error) {
+		return "value", nil
+	})
+	if err != nil || value != "value" {
+		t.Fatalf("expected the value to be fetched again, got %v, %v", value, err)
+	}
+
+	// A read stops waiting once its own context is done
+	blocked := make(chan struct{})
+	defer close(blocked)
+	go func() {
+		_, _ = cache.load(context.Background(), "other", func() (interface{}, error) {
+			<-blocked
+			return nil, nil
+		})
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	for {
+		cache.mu.Lock()
+		_, started := cache.entries["other"]
+		cache.mu.Unlock()
+		if started {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if _, err = cache.load(ctx, "other", func() (interface{}, error) { return nil, nil }); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the read to stop waiting, got %v", err)
+	}
+}
@@ -1,13 +1,15 @@
 package gandi
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceDomain() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -21,23 +23,29 @@ func dataSourceDomain() *schema.Resource {
 				Description: "A list of nameservers for the domain",
 			},
 		},
-		Read: dataSourceDomainRead,
+		ReadContext: dataSourceDomainRead,
 	}
+	for key, s := range domainMetadataSchema() {
+		r.Schema[key] = s
+	}
+	return r
 }
 
-func dataSourceDomainRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*clients).Domain
+func dataSourceDomainRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	name := d.Get("name").(string)
-	found, err := client.GetDomain(name)
+	found, err := meta.(*clients).Cache.domainDetails(ctx, name)
 	if err != nil {
-		return fmt.Errorf("unknown domain '%s': %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("unknown domain '%s': %w", d.Id(), err))
 	}
 	d.SetId(found.FQDN)
 	if err = d.Set("name", found.FQDN); err != nil {
-		return fmt.Errorf("failed to set name for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set name for %s: %w", d.Id(), err))
 	}
 	if err = d.Set("nameservers", found.Nameservers); err != nil {
-		return fmt.Errorf("failed to set nameservers for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set nameservers for %s: %w", d.Id(), err))
+	}
+	if err = setDomainMetadata(d, found); err != nil {
+		return diag.FromErr(err)
 	}
 	return nil
 }
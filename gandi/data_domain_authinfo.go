@@ -0,0 +1,46 @@
+package gandi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceDomainAuthInfo() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"domain": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The FQDN of the domain",
+			},
+			"authinfo": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The authorization code of the domain, to transfer it to another registrar",
+			},
+			"expires_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The expiration date of the authorization code, if any",
+			},
+		},
+		ReadContext: dataSourceDomainAuthInfoRead,
+	}
+}
+
+func dataSourceDomainAuthInfoRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	fqdn := d.Get("domain").(string)
+	details, err := meta.(*clients).Cache.domainDetails(ctx, fqdn)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("unknown domain '%s': %w", fqdn, err))
+	}
+	d.SetId(details.FQDN)
+	if err = setAuthInfo(d, details); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
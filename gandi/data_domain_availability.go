@@ -0,0 +1,223 @@
+package gandi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceDomainAvailability() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceDomainAvailabilityRead,
+		Schema: map[string]*schema.Schema{
+			"fqdns": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				MinItems:     1,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				ExactlyOneOf: []string{"fqdns", "label"},
+				Description:  "The FQDNs of the domains to check",
+			},
+			"label": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"tlds"},
+				Description:  "The label of the domains to check in each of the tlds, such as 'example'",
+			},
+			"tlds": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				MinItems:     1,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				RequiredWith: []string{"label"},
+				Description:  "The TLDs in which the label is checked, such as 'com'",
+			},
+			"currency": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateCurrency,
+				Description:  "The currency of the prices. The currency of the account is used by default.",
+			},
+			"domains": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The availability of the domains, in the order of the fqdns or of the tlds",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"fqdn": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The FQDN of the domain",
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The status of the domain, such as 'available' or 'unavailable'",
+						},
+						"available": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the domain can be registered",
+						},
+						"premium": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the domain is a premium domain",
+						},
+						"currency": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The currency of the prices",
+						},
+						"prices": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The prices of the registration per period",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"min_duration": {
+										Type:        schema.TypeInt,
+										Computed:    true,
+										Description: "The minimum duration of the registration at this price",
+									},
+									"max_duration": {
+										Type:        schema.TypeInt,
+										Computed:    true,
+										Description: "The maximum duration of the registration at this price",
+									},
+									"duration_unit": {
+										Type:        schema.TypeString,
+										Computed:    true,
+										Description: "The unit of the durations, 'y' for years",
+									},
+									"price_before_taxes": {
+										Type:        schema.TypeFloat,
+										Computed:    true,
+										Description: "The price per duration unit, before taxes",
+									},
+									"price_after_taxes": {
+										Type:        schema.TypeFloat,
+										Computed:    true,
+										Description: "The price per duration unit, after taxes",
+									},
+									"discount": {
+										Type:        schema.TypeBool,
+										Computed:    true,
+										Description: "Whether the price is discounted",
+									},
+								},
+							},
+						},
+						"extra_parameters": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "The extra parameters of the owner contact required by the registry",
+						},
+						"phases": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The phases of the TLD, such as its sunrise or its general availability",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"phase": {
+										Type:        schema.TypeString,
+										Computed:    true,
+										Description: "The phase, such as 'golive'",
+									},
+									"name": {
+										Type:        schema.TypeString,
+										Computed:    true,
+										Description: "The name of the phase",
+									},
+									"starts_at": {
+										Type:        schema.TypeString,
+										Computed:    true,
+										Description: "The start date of the phase",
+									},
+									"ends_at": {
+										Type:        schema.TypeString,
+										Computed:    true,
+										Description: "The end date of the phase",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// expandAvailabilityFQDNs returns the FQDNs to check, either listed or
+// made of the label and each of the TLDs.
+func expandAvailabilityFQDNs(d *schema.ResourceData) []string {
+	var fqdns []string
+	if label, ok := d.GetOk("label"); ok {
+		for _, tld := range d.Get("tlds").([]interface{}) {
+			fqdns = append(fqdns, label.(string)+"."+strings.TrimPrefix(tld.(string), "."))
+		}
+		return fqdns
+	}
+	for _, fqdn := range d.Get("fqdns").([]interface{}) {
+		fqdns = append(fqdns, fqdn.(string))
+	}
+	return fqdns
+}
+
+func flattenDomainCheckProduct(product domainCheckProduct, currency string) map[string]interface{} {
+	prices := make([]interface{}, 0, len(product.Prices))
+	for _, p := range product.Prices {
+		prices = append(prices, map[string]interface{}{
+			"min_duration":       p.MinDuration,
+			"max_duration":       p.MaxDuration,
+			"duration_unit":      p.DurationUnit,
+			"price_before_taxes": p.PriceBeforeTaxes,
+			"price_after_taxes":  p.PriceAfterTaxes,
+			"discount":           p.Discount,
+		})
+	}
+	phases := make([]interface{}, 0, len(product.Phases))
+	for _, p := range product.Phases {
+		phases = append(phases, map[string]interface{}{
+			"phase":     p.Phase,
+			"name":      p.Name,
+			"starts_at": p.StartsAt,
+			"ends_at":   p.EndsAt,
+		})
+	}
+	return map[string]interface{}{
+		"fqdn":             product.Name,
+		"status":           product.Status,
+		"available":        product.Status == domainAvailable,
+		"premium":          product.Premium,
+		"currency":         currency,
+		"prices":           prices,
+		"extra_parameters": product.ExtraParameters,
+		"phases":           phases,
+	}
+}
+
+func dataSourceDomainAvailabilityRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*clients).API
+
+	fqdns := expandAvailabilityFQDNs(d)
+	domains := make([]interface{}, 0, len(fqdns))
+	for _, fqdn := range fqdns {
+		product, currency, err := checkDomain(ctx, client, fqdn, d.Get("currency").(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		domains = append(domains, flattenDomainCheckProduct(product, currency))
+	}
+
+	d.SetId(strings.Join(fqdns, ","))
+	if err := d.Set("domains", domains); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set domains for %s: %w", d.Id(), err))
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package gandi
+
+import (
+	"testing"
+)
+
+func TestOfflineDataDomainAvailability_fqdns(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addDomain("taken.com")
+	api.premiumDomains["premium.com"] = true
+	api.domainPrices["premium.com"] = 250
+	p := newTestOfflineProvider(t, api)
+
+	state := p.readDataSource("gandi_domain_availability", map[string]interface{}{
+		"fqdns":    []interface{}{"example.com", "taken.com", "premium.com"},
+		"currency": "USD",
+	})
+	testCheckAttributes(t, state, map[string]string{
+		"id":                                    "example.com,taken.com,premium.com",
+		"domains.#":                             "3",
+		"domains.0.fqdn":                        "example.com",
+		"domains.0.status":                      "available",
+		"domains.0.available":                   "true",
+		"domains.0.premium":                     "false",
+		"domains.0.currency":                    "USD",
+		"domains.0.prices.#":                    "1",
+		"domains.0.prices.0.min_duration":       "1",
+		"domains.0.prices.0.max_duration":       "10",
+		"domains.0.prices.0.duration_unit":      "y",
+		"domains.0.prices.0.price_before_taxes": "12",
+		"domains.0.phases.0.phase":              "golive",
+		"domains.1.status":                      "unavailable",
+		"domains.1.available":                   "false",
+		"domains.2.premium":                     "true",
+		"domains.2.prices.0.price_before_taxes": "250",
+	})
+}
+
+func TestOfflineDataDomainAvailability_label(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.tldExtraParameters["fr"] = []string{"birth_city", "birth_country"}
+	p := newTestOfflineProvider(t, api)
+
+	state := p.readDataSource("gandi_domain_availability", map[string]interface{}{
+		"label": "example",
+		"tlds":  []interface{}{"com", ".fr"},
+	})
+	testCheckAttributes(t, state, map[string]string{
+		"domains.#":                    "2",
+		"domains.0.fqdn":               "example.com",
+		"domains.0.currency":           "EUR",
+		"domains.0.extra_parameters.#": "0",
+		"domains.1.fqdn":               "example.fr",
+		"domains.1.extra_parameters.#": "2",
+		"domains.1.extra_parameters.0": "birth_city",
+	})
+
+	if _, diags := p.readDataSourceWithDiags("gandi_domain_availability", map[string]interface{}{
+		"label": "example",
+	}); !diags.HasError() {
+		t.Fatalf("a label without tlds should be rejected")
+	}
+}
@@ -0,0 +1,229 @@
+package gandi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-gandi/go-gandi/domain"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// The nameserver modes of the domains
+const (
+	nameserverModeLiveDNS  = "livedns"
+	nameserverModeExternal = "external"
+)
+
+func dataSourceDomains() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceDomainsRead,
+		Schema: map[string]*schema.Schema{
+			"tld": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The TLD of the domains, such as 'com'",
+			},
+			"tag": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A tag the domains must have",
+			},
+			"expires_within_days": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validateNonNegativeInt,
+				Description:  "Only list the domains expiring within this number of days",
+			},
+			"nameserver_mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateNameserverMode,
+				Description:  "The nameservers of the domains: 'livedns' for the domains using LiveDNS, 'external' for the other ones",
+			},
+			"autorenew": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Whether the autorenewal of the domains is enabled",
+			},
+			"fqdns": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "The FQDNs of the domains, sorted",
+			},
+			"domains": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The domains, sorted by FQDN",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"fqdn": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The FQDN of the domain",
+						},
+						"tld": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The TLD of the domain",
+						},
+						"autorenew": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the autorenewal of the domain is enabled",
+						},
+						"nameserver_mode": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "'livedns' if the domain uses LiveDNS, 'external' otherwise",
+						},
+						"registry_ends_at": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The expiration date of the domain at the registry, in the RFC 3339 format",
+						},
+						"status": {
+							Type:        schema.TypeList,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Computed:    true,
+							Description: "The statuses of the domain",
+						},
+						"tags": {
+							Type:        schema.TypeList,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Computed:    true,
+							Description: "The tags of the domain",
+						},
+						"sharing_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the organization owning the domain",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func validateNameserverMode(val interface{}, key string) (warns []string, errs []error) {
+	switch v := val.(string); v {
+	case nameserverModeLiveDNS, nameserverModeExternal:
+	default:
+		errs = append(errs, fmt.Errorf("%q must be one of %s or %s. Got %s", key, nameserverModeLiveDNS, nameserverModeExternal, v))
+	}
+	return
+}
+
+// domainFilter selects the listed domains. Its zero value matches
+// every domain.
+type domainFilter struct {
+	tld            string
+	tag            string
+	expiresBefore  *time.Time
+	nameserverMode string
+	autorenew      *bool
+}
+
+func domainNameserverMode(d domain.ListResponse) string {
+	if d.NameServer != nil && d.NameServer.Current == "livedns" {
+		return nameserverModeLiveDNS
+	}
+	return nameserverModeExternal
+}
+
+func (f domainFilter) match(d domain.ListResponse) bool {
+	if f.tld != "" && !strings.EqualFold(d.TLD, strings.TrimPrefix(f.tld, ".")) {
+		return false
+	}
+	if f.tag != "" {
+		tagged := false
+		for _, tag := range d.Tags {
+			tagged = tagged || tag == f.tag
+		}
+		if !tagged {
+			return false
+		}
+	}
+	if f.expiresBefore != nil {
+		if d.Dates == nil || d.Dates.RegistryEndsAt == nil || d.Dates.RegistryEndsAt.After(*f.expiresBefore) {
+			return false
+		}
+	}
+	if f.nameserverMode != "" && domainNameserverMode(d) != f.nameserverMode {
+		return false
+	}
+	if f.autorenew != nil && (d.AutoRenew != nil && *d.AutoRenew) != *f.autorenew {
+		return false
+	}
+	return true
+}
+
+func flattenListedDomain(d domain.ListResponse) map[string]interface{} {
+	registryEndsAt := ""
+	if d.Dates != nil && d.Dates.RegistryEndsAt != nil {
+		registryEndsAt = d.Dates.RegistryEndsAt.UTC().Format(time.RFC3339)
+	}
+	return map[string]interface{}{
+		"fqdn":             d.FQDN,
+		"tld":              d.TLD,
+		"autorenew":        d.AutoRenew != nil && *d.AutoRenew,
+		"nameserver_mode":  domainNameserverMode(d),
+		"registry_ends_at": registryEndsAt,
+		"status":           d.Status,
+		"tags":             d.Tags,
+		"sharing_id":       d.SharingID,
+	}
+}
+
+func dataSourceDomainsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	filter := domainFilter{
+		tld:            d.Get("tld").(string),
+		tag:            d.Get("tag").(string),
+		nameserverMode: d.Get("nameserver_mode").(string),
+	}
+	id := []string{filter.tld, filter.tag, "", filter.nameserverMode, ""}
+	if days, ok := d.GetOk("expires_within_days"); ok {
+		expiresBefore := time.Now().AddDate(0, 0, days.(int))
+		filter.expiresBefore = &expiresBefore
+		id[2] = strconv.Itoa(days.(int))
+	}
+	// autorenew is only used as a filter when it is set, even to false
+	if autorenew, ok := d.GetOkExists("autorenew"); ok {
+		filter.autorenew = Bool(autorenew.(bool))
+		id[4] = strconv.FormatBool(*filter.autorenew)
+	}
+
+	c := meta.(*clients)
+	var listed []domain.ListResponse
+	err := c.call(ctx, func() (err error) {
+		listed, err = c.Domain.ListDomains()
+		return
+	})
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to list the domains: %w", err))
+	}
+	sort.Slice(listed, func(i, j int) bool { return listed[i].FQDN < listed[j].FQDN })
+	fqdns := []string{}
+	domains := make([]interface{}, 0, len(listed))
+	for _, l := range listed {
+		if filter.match(l) {
+			fqdns = append(fqdns, l.FQDN)
+			domains = append(domains, flattenListedDomain(l))
+		}
+	}
+
+	d.SetId("domains/" + strings.Join(id, "/"))
+	if err = d.Set("fqdns", fqdns); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set fqdns for %s: %w", d.Id(), err))
+	}
+	if err = d.Set("domains", domains); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set domains for %s: %w", d.Id(), err))
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package gandi
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOfflineDataDomains_filters(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	for _, fqdn := range []string{"example.com", "example.net", "example.org", "other.com"} {
+		api.addDomain(fqdn)
+	}
+	api.domains["example.com"].details.Tags = []string{"prod"}
+	api.domains["example.com"].details.AutoRenew.Enabled = Bool(true)
+	api.domains["example.net"].liveDNS = false
+	soon := time.Now().UTC().AddDate(0, 0, 10)
+	api.domains["example.org"].details.Dates.RegistryEndsAt = &soon
+	p := newTestOfflineProvider(t, api)
+
+	state := p.readDataSource("gandi_domains", map[string]interface{}{})
+	testCheckAttributes(t, state, map[string]string{
+		"fqdns.#":                    "4",
+		"fqdns.0":                    "example.com",
+		"domains.#":                  "4",
+		"domains.0.fqdn":             "example.com",
+		"domains.0.tld":              "com",
+		"domains.0.autorenew":        "true",
+		"domains.0.nameserver_mode":  "livedns",
+		"domains.0.tags.0":           "prod",
+		"domains.1.nameserver_mode":  "external",
+		"domains.2.registry_ends_at": soon.Truncate(time.Second).Format(time.RFC3339),
+	})
+
+	for _, tc := range []struct {
+		name     string
+		config   map[string]interface{}
+		expected []string
+	}{
+		{"tld", map[string]interface{}{"tld": "com"}, []string{"example.com", "other.com"}},
+		{"tag", map[string]interface{}{"tag": "prod"}, []string{"example.com"}},
+		{"expiry", map[string]interface{}{"expires_within_days": 30}, []string{"example.org"}},
+		{"nameservers", map[string]interface{}{"nameserver_mode": "external"}, []string{"example.net"}},
+		{"autorenew", map[string]interface{}{"autorenew": true}, []string{"example.com"}},
+		{"no autorenew", map[string]interface{}{"autorenew": false, "tld": "com"}, []string{"other.com"}},
+	} {
+		state := p.readDataSource("gandi_domains", tc.config)
+		fqdns := p.provider.DataSourcesMap["gandi_domains"].Data(state).Get("fqdns").([]interface{})
+		if len(fqdns) != len(tc.expected) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, fqdns)
+			continue
+		}
+		for i := range fqdns {
+			if fqdns[i] != tc.expected[i] {
+				t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, fqdns)
+			}
+		}
+	}
+
+	if _, diags := p.readDataSourceWithDiags("gandi_domains", map[string]interface{}{"nameserver_mode": "other"}); !diags.HasError() {
+		t.Fatalf("an unknown nameserver mode should be rejected")
+	}
+}
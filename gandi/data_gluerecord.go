@@ -1,8 +1,11 @@
 package gandi
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/go-gandi/go-gandi/domain"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -26,21 +29,25 @@ func dataSourceGlueRecord() *schema.Resource {
 				Description: "A list of the ip addresses provided for the glue record",
 			},
 		},
-		Read: dataSourceGlueRecordRead,
+		ReadContext: dataSourceGlueRecordRead,
 	}
 }
 
-func dataSourceGlueRecordRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*clients).Domain
+func dataSourceGlueRecordRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
 	zone := d.Get("zone").(string)
 	name := d.Get("name").(string)
-	found, err := client.GetGlueRecord(zone, name)
+	var found domain.GlueRecord
+	err := c.call(ctx, func() (err error) {
+		found, err = c.Domain.GetGlueRecord(zone, name)
+		return
+	})
 	if err != nil {
-		return fmt.Errorf("unknown domain '%s': %w", name, err)
+		return diag.FromErr(fmt.Errorf("unknown domain '%s': %w", name, err))
 	}
 	d.SetId(found.Name)
 	if err = d.Set("ips", found.IPs); err != nil {
-		return fmt.Errorf("failed to set ips for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set ips for %s: %w", d.Id(), err))
 	}
 	return nil
 }
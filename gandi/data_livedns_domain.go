@@ -1,14 +1,17 @@
 package gandi
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/go-gandi/go-gandi/livedns"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceLiveDNSDomain() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceLiveDNSDomainRead,
+		ReadContext: dataSourceLiveDNSDomainRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -19,16 +22,20 @@ func dataSourceLiveDNSDomain() *schema.Resource {
 	}
 }
 
-func dataSourceLiveDNSDomainRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*clients).LiveDNS
+func dataSourceLiveDNSDomainRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
 	name := d.Get("name").(string)
-	found, err := client.GetDomain(name)
+	var found livedns.Domain
+	err := c.call(ctx, func() (err error) {
+		found, err = c.LiveDNS.GetDomain(name)
+		return
+	})
 	if err != nil {
-		return fmt.Errorf("unknown domain '%s': %w", name, err)
+		return diag.FromErr(fmt.Errorf("unknown domain '%s': %w", name, err))
 	}
 	d.SetId(found.FQDN)
 	if err = d.Set("name", found.FQDN); err != nil {
-		return fmt.Errorf("failed to set name for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set name for %s: %w", d.Id(), err))
 	}
 	return nil
 }
@@ -1,14 +1,16 @@
 package gandi
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceLiveDNSDomainNS() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceLiveDNSDomainNSRead,
+		ReadContext: dataSourceLiveDNSDomainNSRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -25,17 +27,21 @@ func dataSourceLiveDNSDomainNS() *schema.Resource {
 	}
 }
 
-func dataSourceLiveDNSDomainNSRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*clients).LiveDNS
+func dataSourceLiveDNSDomainNSRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
 	name := d.Get("name").(string)
-	ns, err := client.GetDomainNS(name)
+	var ns []string
+	err := c.call(ctx, func() (err error) {
+		ns, err = c.LiveDNS.GetDomainNS(name)
+		return
+	})
 	if err != nil {
 		ns = []string{}
 	}
 
 	d.SetId(name)
 	if err = d.Set("nameservers", ns); err != nil {
-		return fmt.Errorf("failed to set nameservers for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set nameservers for %s: %w", d.Id(), err))
 	}
 	return nil
 }
@@ -0,0 +1,137 @@
+package gandi
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-gandi/go-gandi/livedns"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceLiveDNSRecords() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceLiveDNSRecordsRead,
+		Schema: map[string]*schema.Schema{
+			"zone": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The FQDN of the domain",
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp,
+				Description:  "A regular expression the name of the records must match",
+			},
+			"type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The type of the records",
+			},
+			"records": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The records of the zone, sorted by name and type",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the record",
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The type of the record",
+						},
+						"ttl": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The TTL of the record",
+						},
+						"values": {
+							Type:        schema.TypeList,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Computed:    true,
+							Description: "A list of values of the record",
+						},
+						"href": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The API URL of the record",
+						},
+					},
+				},
+			},
+			"zonefile": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The records rendered as a BIND zone file",
+			},
+		},
+	}
+}
+
+func validateRegexp(val interface{}, key string) (warns []string, errs []error) {
+	if _, err := regexp.Compile(val.(string)); err != nil {
+		errs = append(errs, fmt.Errorf("%q must be a valid regular expression: %w", key, err))
+	}
+	return
+}
+
+// filterRecords returns the records whose name matches nameRegex and
+// whose type is recordType. Empty filters match every record.
+func filterRecords(records []livedns.DomainRecord, nameRegex *regexp.Regexp, recordType string) []livedns.DomainRecord {
+	var filtered []livedns.DomainRecord
+	for _, r := range records {
+		if nameRegex != nil && !nameRegex.MatchString(r.RrsetName) {
+			continue
+		}
+		if recordType != "" && !strings.EqualFold(r.RrsetType, recordType) {
+			continue
+		}
+		filtered = append(filtered, r)
+	}
+	return filtered
+}
+
+func flattenRecords(records []livedns.DomainRecord) []interface{} {
+	flattened := make([]interface{}, 0, len(records))
+	for _, r := range records {
+		flattened = append(flattened, map[string]interface{}{
+			"name":   r.RrsetName,
+			"type":   r.RrsetType,
+			"ttl":    r.RrsetTTL,
+			"values": r.RrsetValues,
+			"href":   r.RrsetHref,
+		})
+	}
+	return flattened
+}
+
+func dataSourceLiveDNSRecordsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zone := d.Get("zone").(string)
+	records, err := getZoneRecords(ctx, meta.(*clients).Cache, zone, false)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to get the records of zone '%s': %w", zone, err))
+	}
+
+	var nameRegex *regexp.Regexp
+	if v, ok := d.GetOk("name_regex"); ok {
+		nameRegex = regexp.MustCompile(v.(string))
+	}
+	recordType := d.Get("type").(string)
+	records = filterRecords(records, nameRegex, recordType)
+
+	d.SetId(fmt.Sprintf("%s/%s/%s", zone, d.Get("name_regex").(string), recordType))
+	if err = d.Set("records", flattenRecords(records)); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set records for %s: %w", d.Id(), err))
+	}
+	if err = d.Set("zonefile", renderZoneFile(zone, records)); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set zonefile for %s: %w", d.Id(), err))
+	}
+	return nil
+}
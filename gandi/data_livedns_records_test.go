@@ -0,0 +1,50 @@
+package gandi
+
+import (
+	"testing"
+
+	"github.com/go-gandi/go-gandi/livedns"
+)
+
+func TestOfflineDataRecords_basic(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addZone("example.com",
+		livedns.DomainRecord{RrsetName: "@", RrsetType: "NS", RrsetValues: fakeLiveDNSNameservers},
+		livedns.DomainRecord{RrsetName: "www", RrsetType: "A", RrsetTTL: 300, RrsetValues: []string{"192.168.0.1"}},
+		livedns.DomainRecord{RrsetName: "www", RrsetType: "TXT", RrsetTTL: 300, RrsetValues: []string{"hello"}},
+		livedns.DomainRecord{RrsetName: "api", RrsetType: "A", RrsetTTL: 600, RrsetValues: []string{"192.168.0.2"}},
+	)
+	p := newTestOfflineProvider(t, api)
+
+	state := p.readDataSource("gandi_livedns_records", map[string]interface{}{"zone": "example.com"})
+	testCheckAttributes(t, state, map[string]string{
+		"records.#":          "4",
+		"records.0.name":     "@",
+		"records.0.type":     "NS",
+		"records.1.name":     "api",
+		"records.1.ttl":      "600",
+		"records.1.href":     api.server.URL + "/v5/livedns/domains/example.com/records/api/A",
+		"records.3.type":     "TXT",
+		"records.3.values.0": `"hello"`,
+	})
+
+	state = p.readDataSource("gandi_livedns_records", map[string]interface{}{
+		"zone":       "example.com",
+		"name_regex": "^w+$",
+		"type":       "a",
+	})
+	testCheckAttributes(t, state, map[string]string{
+		"records.#":          "1",
+		"records.0.name":     "www",
+		"records.0.values.0": "192.168.0.1",
+		"zonefile":           "$ORIGIN example.com.\nwww 300 IN A 192.168.0.1\n",
+	})
+
+	_, diags := p.readDataSourceWithDiags("gandi_livedns_records", map[string]interface{}{
+		"zone":       "example.com",
+		"name_regex": "(",
+	})
+	if !diags.HasError() {
+		t.Fatalf("expected an invalid regular expression to be rejected")
+	}
+}
@@ -0,0 +1,152 @@
+package gandi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-gandi/go-gandi/livedns"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceLiveDNSSnapshots() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceLiveDNSSnapshotsRead,
+		Schema: map[string]*schema.Schema{
+			"zone": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The FQDN of the domain",
+			},
+			"include_records": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the records and the zonefile of each snapshot are read, with one request per snapshot",
+			},
+			"snapshots": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The snapshots of the zone, from the oldest to the newest",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the snapshot",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the snapshot",
+						},
+						"automatic": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the snapshot was taken automatically",
+						},
+						"created_at": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The creation date of the snapshot",
+						},
+						"records": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The records of the snapshot, when include_records is true",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"name": {
+										Type:        schema.TypeString,
+										Computed:    true,
+										Description: "The name of the record",
+									},
+									"type": {
+										Type:        schema.TypeString,
+										Computed:    true,
+										Description: "The type of the record",
+									},
+									"ttl": {
+										Type:        schema.TypeInt,
+										Computed:    true,
+										Description: "The TTL of the record",
+									},
+									"values": {
+										Type:        schema.TypeList,
+										Elem:        &schema.Schema{Type: schema.TypeString},
+										Computed:    true,
+										Description: "A list of values of the record",
+									},
+								},
+							},
+						},
+						"zonefile": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The records of the snapshot rendered as a BIND zone file, when include_records is true",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceLiveDNSSnapshotsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
+	zone := d.Get("zone").(string)
+	var list []livedns.Snapshot
+	err := c.call(ctx, func() (err error) {
+		list, err = c.LiveDNS.ListSnapshots(zone)
+		return
+	})
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to list the snapshots of zone '%s': %w", zone, err))
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].CreatedAt.Before(list[j].CreatedAt)
+	})
+
+	snapshots := make([]interface{}, 0, len(list))
+	for _, s := range list {
+		snapshot := map[string]interface{}{
+			"id":         s.ID,
+			"name":       s.Name,
+			"automatic":  s.Automatic != nil && *s.Automatic,
+			"created_at": s.CreatedAt.Format(time.RFC3339),
+		}
+		// The list doesn't contain the records of the snapshots
+		if d.Get("include_records").(bool) {
+			s := s
+			err := c.call(ctx, func() (err error) {
+				s, err = c.LiveDNS.GetSnapshot(zone, s.ID)
+				return
+			})
+			if err != nil {
+				return diag.FromErr(fmt.Errorf("failed to get the snapshot %s of zone '%s': %w", s.ID, zone, err))
+			}
+			records := s.ZoneData
+			flattened := make([]interface{}, 0, len(records))
+			for i, r := range records {
+				records[i].RrsetValues = joinTXTValues(r.RrsetType, r.RrsetValues)
+				flattened = append(flattened, map[string]interface{}{
+					"name":   r.RrsetName,
+					"type":   r.RrsetType,
+					"ttl":    r.RrsetTTL,
+					"values": records[i].RrsetValues,
+				})
+			}
+			snapshot["records"] = flattened
+			snapshot["zonefile"] = renderZoneFile(zone, records)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	d.SetId(zone)
+	if err = d.Set("snapshots", snapshots); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set snapshots for %s: %w", d.Id(), err))
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package gandi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceLiveDNSZoneFile() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceLiveDNSZoneFileRead,
+		Schema: map[string]*schema.Schema{
+			"zone": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The FQDN of the domain",
+			},
+			"content": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The records of the zone as a zone file, without the SOA and apex NS records managed by Gandi",
+			},
+		},
+	}
+}
+
+func dataSourceLiveDNSZoneFileRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zone := d.Get("zone").(string)
+	records, err := getZoneRecords(ctx, meta.(*clients).Cache, zone, true)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to get the records of zone '%s': %w", zone, err))
+	}
+	d.SetId(zone)
+	if err = d.Set("content", renderZoneFile(zone, withoutZoneFileManagedRecords(records))); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set content for %s: %w", d.Id(), err))
+	}
+	return nil
+}
@@ -1,8 +1,11 @@
 package gandi
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/go-gandi/go-gandi/email"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -19,42 +22,77 @@ func dataSourceMailbox() *schema.Resource {
 				Required:    true,
 				Description: "Mailbox ID",
 			},
+			"login": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Login",
+			},
+			"mailbox_type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Mailbox type",
+			},
+			"aliases": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "Aliases for email",
+			},
+			"address": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The email address of the mailbox",
+			},
+			"href": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The href of the mailbox",
+			},
+			"quota_used": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The mailbox quota used",
+			},
 		},
-		Read: dataSourceMailboxRead,
+		ReadContext: dataSourceMailboxRead,
 	}
 }
 
-func dataSourceMailboxRead(d *schema.ResourceData, meta interface{}) (err error) {
-	client := meta.(*clients).Email
+func dataSourceMailboxRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
 	domain := d.Get("domain").(string)
 	id := d.Get("mailbox_id").(string)
 
-	found, err := client.GetMailbox(domain, id)
-	if err != nil {
+	var found email.MailboxResponse
+	err := c.call(ctx, func() (err error) {
+		found, err = c.Email.GetMailbox(domain, id)
 		return
+	})
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	d.SetId(id)
 	if err = d.Set("address", found.Address); err != nil {
-		return fmt.Errorf("failed to set address for %s: %s", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set address for %s: %s", d.Id(), err))
 	}
 	if err = d.Set("aliases", found.Aliases); err != nil {
-		return fmt.Errorf("failed to set aliases for %s: %s", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set aliases for %s: %s", d.Id(), err))
 	}
 	if err = d.Set("domain", found.Domain); err != nil {
-		return fmt.Errorf("failed to set domain for %s: %s", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set domain for %s: %s", d.Id(), err))
 	}
 	if err = d.Set("href", found.Href); err != nil {
-		return fmt.Errorf("failed to set href for %s: %s", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set href for %s: %s", d.Id(), err))
 	}
 	if err = d.Set("quota_used", found.QuotaUsed); err != nil {
-		return fmt.Errorf("failed to set quota_used for %s: %s", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set quota_used for %s: %s", d.Id(), err))
 	}
 	if err = d.Set("login", found.Login); err != nil {
-		return fmt.Errorf("failed to set login for %s: %s", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set login for %s: %s", d.Id(), err))
 	}
 	if err = d.Set("mailbox_type", found.MailboxType); err != nil {
-		return fmt.Errorf("failed to set mailbox_type for %s: %s", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set mailbox_type for %s: %s", d.Id(), err))
 	}
 	return nil
 }
@@ -0,0 +1,129 @@
+package gandi
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-gandi/go-gandi/types"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// apiResponseError is the error of a types.RequestError returned by
+// the apiClient. Unlike go-gandi, it keeps the field errors of the
+// response.
+type apiResponseError struct {
+	StatusCode int
+	Response   types.StandardResponse
+}
+
+func (e *apiResponseError) Error() string {
+	switch {
+	case e.Response.Message != "":
+		return fmt.Sprintf("%d: %s", e.StatusCode, e.Response.Message)
+	case len(e.Response.Errors) > 0:
+		var errors []string
+		for _, fieldError := range e.Response.Errors {
+			errors = append(errors, fmt.Sprintf("%s: %s", fieldError.Name, fieldError.Description))
+		}
+		return strings.Join(errors, ", ")
+	default:
+		return fmt.Sprintf("%d", e.StatusCode)
+	}
+}
+
+// requestFieldErrors returns the field errors of the Gandi API request
+// error wrapped by err, if any. Only the errors of the apiClient keep
+// them: go-gandi flattens them into the message of its errors.
+func requestFieldErrors(err error) (*types.RequestError, []types.StandardError) {
+	var requestError *types.RequestError
+	if !errors.As(err, &requestError) || requestError.Err == nil {
+		return nil, nil
+	}
+	var responseError *apiResponseError
+	if !errors.As(requestError.Err, &responseError) {
+		return requestError, nil
+	}
+	return requestError, responseError.Response.Errors
+}
+
+// apiFieldPath returns the path of the attribute of a resource schema
+// matching the field of a Gandi API error, such as owner for the
+// owner.phone field of a domain, or nil if no attribute matches. The
+// path stops at the sets, whose elements have no index.
+// The fields whose API name differs from the attribute name are
+// translated with the fields map, whose keys are either a single
+// segment or a dotted API name.
+func apiFieldPath(s map[string]*schema.Schema, fields map[string]string, name string) cty.Path {
+	var path cty.Path
+	segments := strings.Split(name, ".")
+	for i := 0; i < len(segments) && s != nil; i++ {
+		attribute := segments[i]
+		if i+1 < len(segments) {
+			if a, ok := fields[segments[i]+"."+segments[i+1]]; ok {
+				attribute = a
+				i++
+			}
+		}
+		if a, ok := fields[attribute]; ok {
+			attribute = a
+		}
+		attributeSchema, ok := s[attribute]
+		if !ok {
+			break
+		}
+		path = path.GetAttr(attribute)
+		if attributeSchema.Type == schema.TypeSet {
+			break
+		}
+
+		s = nil
+		if attributeSchema.Type != schema.TypeList {
+			continue
+		}
+		index, indexed := 0, false
+		if i+1 < len(segments) {
+			if n, err := strconv.Atoi(segments[i+1]); err == nil {
+				index, indexed = n, true
+				i++
+			}
+		}
+		resource, isBlock := attributeSchema.Elem.(*schema.Resource)
+		if isBlock || indexed {
+			// The blocks are assumed to have a single element unless
+			// the field gives the index
+			path = path.IndexInt(index)
+		}
+		if isBlock {
+			s = resource.Schema
+		}
+	}
+	return path
+}
+
+// diagFromRequestError converts an error into diagnostics. When the
+// error is a Gandi API request error describing invalid fields, it is
+// split into one diagnostic per field, attached to the matching
+// attribute of the resource schema s.
+func diagFromRequestError(err error, s map[string]*schema.Schema, fields map[string]string) diag.Diagnostics {
+	if err == nil {
+		return nil
+	}
+	requestError, fieldErrors := requestFieldErrors(err)
+	if len(fieldErrors) == 0 {
+		return diag.FromErr(err)
+	}
+	var diags diag.Diagnostics
+	for _, fieldError := range fieldErrors {
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       fmt.Sprintf("%s: %s", fieldError.Name, fieldError.Description),
+			Detail:        fmt.Sprintf("The Gandi API rejected the request with the status code %d: %s", requestError.StatusCode, err),
+			AttributePath: apiFieldPath(s, fields, fieldError.Name),
+		})
+	}
+	return diags
+}
@@ -0,0 +1,89 @@
+package gandi
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-gandi/go-gandi/types"
+	"github.com/hashicorp/go-cty/cty"
+)
+
+func TestRequestFieldErrors(t *testing.T) {
+	// The field errors flattened by go-gandi are not parsed back
+	for _, err := range []error{
+		errors.New("not a request error"),
+		&types.RequestError{StatusCode: 400, Err: errors.New("owner.phone: Invalid phone, owner.email: Not an email")},
+		&types.RequestError{StatusCode: 404, Err: errors.New("404: The resource could not be found.")},
+		&types.RequestError{StatusCode: 500, Err: errors.New("500")},
+	} {
+		if _, fieldErrors := requestFieldErrors(err); len(fieldErrors) != 0 {
+			t.Fatalf("expected no field error for %q, got %v", err, fieldErrors)
+		}
+	}
+
+	// The apiClient keeps the field errors along with the message
+	err := fmt.Errorf("failed to create the domain: %w", &types.RequestError{StatusCode: 400, Err: &apiResponseError{
+		StatusCode: 400,
+		Response: types.StandardResponse{
+			Message: "Bad Request",
+			Errors:  []types.StandardError{{Name: "name", Description: "Too long"}},
+		},
+	}})
+	if _, fieldErrors := requestFieldErrors(err); len(fieldErrors) != 1 || fieldErrors[0].Name != "name" {
+		t.Fatalf("expected the field errors of the response, got %v", fieldErrors)
+	}
+}
+
+func TestAPIFieldPath(t *testing.T) {
+	domainSchema := resourceDomain().Schema
+	for _, tc := range []struct {
+		name     string
+		expected cty.Path
+	}{
+		// The contacts are sets, whose elements have no index
+		{"owner.phone", cty.GetAttrPath("owner")},
+		{"bill.given", cty.GetAttrPath("billing")},
+		{"tech.0.streetaddr", cty.GetAttrPath("tech")},
+		{"owner_change.status", cty.GetAttrPath("owner_change").IndexInt(0).GetAttr("status")},
+		{"owner_change.0.unknown", cty.GetAttrPath("owner_change").IndexInt(0)},
+		{"nameservers.1", cty.GetAttrPath("nameservers").IndexInt(1)},
+		{"nameservers", cty.GetAttrPath("nameservers")},
+		{"fqdn", cty.GetAttrPath("name")},
+		{"unknown", nil},
+	} {
+		if path := apiFieldPath(domainSchema, domainAPIFields, tc.name); !path.Equals(tc.expected) {
+			t.Errorf("expected %#v for %s, got %#v", tc.expected, tc.name, path)
+		}
+	}
+
+	path := apiFieldPath(resourceSimpleHostingInstance().Schema, simpleHostingInstanceAPIFields, "type.database.name")
+	if !path.Equals(cty.GetAttrPath("database_name")) {
+		t.Errorf("expected the database_name attribute, got %#v", path)
+	}
+}
+
+func TestOfflineDomain_invalidContact(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	p := newTestOfflineProvider(t, api)
+	owner := testOfflineContact("owner@example.com")
+	owner[0].(map[string]interface{})["phone"] = "0606060606"
+	tech := testOfflineContact("tech@example.com")
+	tech[0].(map[string]interface{})["phone"] = "+33 6 06 06 06 06"
+
+	_, diags := p.applyWithDiags("gandi_domain", nil, testOfflineDomainConfig(map[string]interface{}{
+		"owner": owner,
+		"tech":  tech,
+	}))
+	if len(diags) != 2 {
+		t.Fatalf("expected one diagnostic per invalid field, got %v", diags)
+	}
+	for i, expected := range []cty.Path{
+		cty.GetAttrPath("owner"),
+		cty.GetAttrPath("tech"),
+	} {
+		if !diags[i].AttributePath.Equals(expected) {
+			t.Errorf("expected the diagnostic %q to be attached to %#v, got %#v", diags[i].Summary, expected, diags[i].AttributePath)
+		}
+	}
+}
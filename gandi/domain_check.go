@@ -0,0 +1,134 @@
+package gandi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// domainCheck is the response of the domain/check endpoint, which
+// go-gandi doesn't cover
+type domainCheck struct {
+	Currency string               `json:"currency"`
+	Grid     string               `json:"grid"`
+	Products []domainCheckProduct `json:"products"`
+}
+
+// domainCheckProduct describes the availability and the prices of a
+// domain for a process, such as its creation
+type domainCheckProduct struct {
+	Name    string             `json:"name"`
+	Status  string             `json:"status"`
+	Process string             `json:"process"`
+	Premium bool               `json:"premium,omitempty"`
+	Prices  []domainCheckPrice `json:"prices"`
+	Phases  []domainCheckPhase `json:"phases"`
+	// ExtraParameters are the extra parameters of the owner contact
+	// required by the registry
+	ExtraParameters []string `json:"extra_parameters,omitempty"`
+}
+
+// domainCheckPrice is the price of the registrations lasting between
+// MinDuration and MaxDuration, in DurationUnit. The prices are those
+// of a single DurationUnit: a registration costs as many times the
+// price as it lasts units.
+type domainCheckPrice struct {
+	MinDuration      int     `json:"min_duration"`
+	MaxDuration      int     `json:"max_duration"`
+	DurationUnit     string  `json:"duration_unit"`
+	PriceBeforeTaxes float64 `json:"price_before_taxes"`
+	PriceAfterTaxes  float64 `json:"price_after_taxes"`
+	Discount         bool    `json:"discount"`
+}
+
+type domainCheckPhase struct {
+	Phase    string `json:"phase"`
+	Name     string `json:"name"`
+	StartsAt string `json:"starts_at,omitempty"`
+	EndsAt   string `json:"ends_at,omitempty"`
+}
+
+// domainAvailable is the status of a domain which can be registered
+const domainAvailable = "available"
+
+// checkDomain returns the availability and the prices of the
+// registration of a domain, in the currency if it isn't empty.
+func checkDomain(ctx context.Context, client *apiClient, fqdn, currency string) (domainCheckProduct, string, error) {
+	query := url.Values{"name": {fqdn}, "processes": {"create"}}
+	if currency != "" {
+		query.Set("currency", currency)
+	}
+	var check domainCheck
+	if err := client.Get(ctx, "domain/check?"+query.Encode(), &check); err != nil {
+		return domainCheckProduct{}, "", fmt.Errorf("failed to check the availability of %s: %w", fqdn, err)
+	}
+	for _, product := range check.Products {
+		if product.Name == fqdn && (product.Process == "" || product.Process == "create") {
+			return product, check.Currency, nil
+		}
+	}
+	return domainCheckProduct{}, "", fmt.Errorf("failed to check the availability of %s: the domain is missing from the response", fqdn)
+}
+
+// durationUnitMonths is the number of months of the duration units of
+// the prices
+var durationUnitMonths = map[string]int{"y": 12, "m": 1}
+
+// registrationPrice returns the price of the registration for the
+// duration in years, before taxes, if it applies to this duration. The
+// duration is converted to the unit of the price, which is ignored if
+// its unit is unknown.
+func (price domainCheckPrice) registrationPrice(duration int) (float64, bool) {
+	months, ok := durationUnitMonths[price.DurationUnit]
+	if !ok || 12*duration%months != 0 {
+		return 0, false
+	}
+	units := 12 * duration / months
+	if units < price.MinDuration || units > price.MaxDuration {
+		return 0, false
+	}
+	return price.PriceBeforeTaxes * float64(units), true
+}
+
+// registrationPrice returns the price of the registration of a domain
+// for the duration in years, before taxes, from the first price which
+// applies to this duration.
+func (p domainCheckProduct) registrationPrice(duration int) (float64, bool) {
+	for _, price := range p.Prices {
+		if total, ok := price.registrationPrice(duration); ok {
+			return total, true
+		}
+	}
+	return 0, false
+}
+
+// domainOrder is what the registration of a domain is allowed to cost
+type domainOrder struct {
+	duration      int
+	maxPrice      float64
+	acceptPremium bool
+}
+
+// check returns an error if the domain can't be registered as ordered
+func (o domainOrder) check(product domainCheckProduct, currency string) error {
+	if product.Status != domainAvailable {
+		return fmt.Errorf("the domain %s can't be registered: its status is %s", product.Name, product.Status)
+	}
+	if product.Premium && !o.acceptPremium {
+		return fmt.Errorf("the domain %s is a premium domain: set accept_premium to register it", product.Name)
+	}
+	price, ok := product.registrationPrice(o.duration)
+	if !ok {
+		return fmt.Errorf("the domain %s can't be registered for %d years", product.Name, o.duration)
+	}
+	if o.maxPrice > 0 && price > o.maxPrice {
+		return fmt.Errorf("the registration of %s for %d years costs %s %s before taxes, above the max_price of %s %s",
+			product.Name, o.duration, formatPrice(price), currency, formatPrice(o.maxPrice), currency)
+	}
+	return nil
+}
+
+func formatPrice(price float64) string {
+	return strconv.FormatFloat(price, 'f', 2, 64)
+}
@@ -0,0 +1,98 @@
+package gandi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-gandi/go-gandi/domain"
+	"github.com/go-gandi/go-gandi/types"
+)
+
+// ownerChange is the status of the change of the owner of a domain, a
+// trade, as returned by the domain/changeowner endpoint which go-gandi
+// doesn't cover
+type ownerChange struct {
+	Owner  *domain.Contact `json:"owner,omitempty"`
+	Status string          `json:"status"`
+	// FOAStatus is the status of the confirmation of the change, the
+	// form of authorization, by each of the contacts who must approve
+	// it, by email address
+	FOAStatus map[string]string `json:"foa_status,omitempty"`
+}
+
+const ownerChangeDone = "done"
+
+// ownerChangeFailed are the statuses of owner changes which won't
+// complete. Any other status is considered as pending.
+var ownerChangeFailed = map[string]bool{
+	"error":    true,
+	"failed":   true,
+	"canceled": true,
+	"refused":  true,
+}
+
+// pending returns whether the owner change is still in progress
+func (c ownerChange) pending() bool {
+	return c.Status != ownerChangeDone && !ownerChangeFailed[c.Status]
+}
+
+// pendingConfirmations returns the email addresses which have not
+// confirmed the owner change yet, sorted.
+func (c ownerChange) pendingConfirmations() []string {
+	emails := []string{}
+	for email, status := range c.FOAStatus {
+		switch status {
+		case "done", "accepted", "confirmed":
+		default:
+			emails = append(emails, email)
+		}
+	}
+	sort.Strings(emails)
+	return emails
+}
+
+// sameOwner returns whether the owner change is a change to owner, to
+// resume it instead of submitting it again.
+func (c ownerChange) sameOwner(owner *domain.Contact) bool {
+	return c.Owner != nil && owner != nil &&
+		strings.EqualFold(c.Owner.Email, owner.Email) &&
+		c.Owner.GivenName == owner.GivenName &&
+		c.Owner.FamilyName == owner.FamilyName &&
+		c.Owner.OrgName == owner.OrgName
+}
+
+func flattenOwnerChange(c *ownerChange) []interface{} {
+	if c == nil {
+		return []interface{}{}
+	}
+	pendingConfirmations := c.pendingConfirmations()
+	return []interface{}{map[string]interface{}{
+		"status":                c.Status,
+		"pending":               c.pending(),
+		"confirmation_required": c.pending() && len(pendingConfirmations) > 0,
+		"pending_confirmations": pendingConfirmations,
+	}}
+}
+
+// getOwnerChange returns the last owner change of a domain, or nil if
+// its owner has never been changed.
+func getOwnerChange(ctx context.Context, client *apiClient, fqdn string) (*ownerChange, error) {
+	var change ownerChange
+	if err := client.Get(ctx, "domain/changeowner/"+fqdn, &change); err != nil {
+		if requestError, ok := err.(*types.RequestError); ok && requestError.StatusCode == 404 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the owner change of %s: %w", fqdn, err)
+	}
+	return &change, nil
+}
+
+// changeOwner submits the change of the owner of a domain
+func changeOwner(ctx context.Context, client *apiClient, fqdn string, owner *domain.Contact) error {
+	request := struct {
+		Owner *domain.Contact `json:"owner"`
+	}{Owner: owner}
+	return client.Post(ctx, "domain/changeowner/"+fqdn, request, nil)
+}
@@ -0,0 +1,1419 @@
+package gandi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-gandi/go-gandi/certificate"
+	"github.com/go-gandi/go-gandi/domain"
+	"github.com/go-gandi/go-gandi/email"
+	"github.com/go-gandi/go-gandi/livedns"
+	"github.com/go-gandi/go-gandi/simplehosting"
+	"github.com/go-gandi/go-gandi/types"
+)
+
+// fakeGandiAPI is an in-memory stand-in for the parts of the Gandi v5
+// API used by this provider. It is served by an httptest server and
+// plugged into the provider through the url attribute, so resources
+// can be exercised without a Gandi account nor any network access.
+type fakeGandiAPI struct {
+	server *httptest.Server
+
+	mu           sync.Mutex
+	nextID       int
+	requests     []string
+	domains      map[string]*fakeDomain
+	zones        map[string]*fakeZone
+	mailboxes    map[string]map[string]*email.MailboxResponse
+	forwards     map[string]map[string]*email.GetForwardRequest
+	instances    map[string]*simplehosting.Instance
+	vhosts       map[string]map[string]*simplehosting.Vhost
+	certificates map[string]*certificate.CertificateType
+
+	// beforeRequest, when set, is called with the lock held before
+	// each request is served, to simulate concurrent writers
+	beforeRequest func(r *http.Request)
+	// throttled is the number of the next requests rejected with a
+	// 429 status code, as when the rate limit is exceeded
+	throttled int
+	// domainPrices is the yearly price of the registration of the
+	// domains, before taxes, fakeDomainPrice by default
+	domainPrices map[string]float64
+	// premiumDomains are the domains which can only be registered with
+	// enforce_premium
+	premiumDomains map[string]bool
+	// tldExtraParameters are the extra parameters of the owner contact
+	// required by the registry of a TLD
+	tldExtraParameters map[string][]string
+	// ownerChangePolls is the number of times the status of an owner
+	// change is read as pending before it is done, as if the contacts
+	// confirmed it meanwhile. A negative value keeps it pending.
+	ownerChangePolls int
+	// ownerChangeForbidden rejects the reads of the owner changes, as
+	// for an API key without the rights to change the owners
+	ownerChangeForbidden bool
+	// pendingRenewals accepts the renewals without moving the
+	// expiration dates, as when the registry didn't process them yet
+	pendingRenewals bool
+	// releasableTLDs are the TLDs whose domains can be deleted
+	releasableTLDs map[string]bool
+	// releaseErrors are the status codes answered to the deletion of
+	// the domains of the other TLDs, 403 by default
+	releaseErrors map[string]int
+	transfers     map[string]*domainTransfer
+	// transferRequests are the requests starting the transfers
+	transferRequests map[string]domainTransferRequest
+}
+
+// fakeDomainPrice is the default yearly price of a domain registration
+const fakeDomainPrice = 12.0
+
+type fakeDomain struct {
+	details     domain.Details
+	ownerChange *fakeOwnerChange
+	liveDNS     bool
+	dnssecKeys  []domain.DNSSECKey
+	hosts       map[string]*domain.GlueRecord
+}
+
+type fakeOwnerChange struct {
+	ownerChange
+	polls int
+}
+
+type fakeZone struct {
+	domain    livedns.Domain
+	records   []livedns.DomainRecord
+	snapshots []livedns.Snapshot
+}
+
+var fakeLiveDNSNameservers = []string{
+	"ns-1-a.gandi.net",
+	"ns-2-b.gandi.net",
+	"ns-3-c.gandi.net",
+}
+
+func newFakeGandiAPI(t *testing.T) *fakeGandiAPI {
+	api := &fakeGandiAPI{
+		domains:            make(map[string]*fakeDomain),
+		zones:              make(map[string]*fakeZone),
+		mailboxes:          make(map[string]map[string]*email.MailboxResponse),
+		forwards:           make(map[string]map[string]*email.GetForwardRequest),
+		instances:          make(map[string]*simplehosting.Instance),
+		vhosts:             make(map[string]map[string]*simplehosting.Vhost),
+		certificates:       make(map[string]*certificate.CertificateType),
+		domainPrices:       make(map[string]float64),
+		premiumDomains:     make(map[string]bool),
+		tldExtraParameters: make(map[string][]string),
+		releasableTLDs:     make(map[string]bool),
+		releaseErrors:      make(map[string]int),
+		transfers:          make(map[string]*domainTransfer),
+		transferRequests:   make(map[string]domainTransferRequest),
+	}
+	api.server = httptest.NewServer(http.HandlerFunc(api.serveHTTP))
+	t.Cleanup(api.server.Close)
+	return api
+}
+
+// requestCount returns the number of requests received so far whose
+// "METHOD path" description starts with prefix.
+func (api *fakeGandiAPI) requestCount(prefix string) int {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	count := 0
+	for _, r := range api.requests {
+		if strings.HasPrefix(r, prefix) {
+			count++
+		}
+	}
+	return count
+}
+
+func (api *fakeGandiAPI) newID() string {
+	api.nextID++
+	return fmt.Sprintf("00000000-0000-0000-0000-%012d", api.nextID)
+}
+
+// addDomain registers a domain, as if it had been bought beforehand.
+func (api *fakeGandiAPI) addDomain(fqdn string) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	api.createDomain(domain.CreateRequest{
+		FQDN:  fqdn,
+		Owner: &domain.Contact{FamilyName: "Doe", GivenName: "John", Email: "john@example.com", Country: "FR", DataObfuscated: Bool(true), MailObfuscated: Bool(true)},
+	})
+}
+
+// addZone creates a LiveDNS zone containing the given records.
+func (api *fakeGandiAPI) addZone(fqdn string, records ...livedns.DomainRecord) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	api.zones[fqdn] = &fakeZone{
+		domain:  livedns.Domain{FQDN: fqdn, AutomaticSnapshots: Bool(true)},
+		records: normalizeFakeRecords(records),
+	}
+}
+
+// zoneRecords returns a copy of the records of a LiveDNS zone.
+func (api *fakeGandiAPI) zoneRecords(fqdn string) []livedns.DomainRecord {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	zone, ok := api.zones[fqdn]
+	if !ok {
+		return nil
+	}
+	return append([]livedns.DomainRecord{}, zone.records...)
+}
+
+// record returns the values of a rrset, or nil if it doesn't exist.
+func (api *fakeGandiAPI) record(fqdn, name, recordType string) []string {
+	for _, r := range api.zoneRecords(fqdn) {
+		if r.RrsetName == name && r.RrsetType == recordType {
+			return r.RrsetValues
+		}
+	}
+	return nil
+}
+
+// setRecord replaces a rrset, behind the provider's back.
+func (api *fakeGandiAPI) setRecord(fqdn string, record livedns.DomainRecord) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	zone := api.zones[fqdn]
+	zone.removeRecord(record.RrsetName, record.RrsetType)
+	zone.records = append(zone.records, normalizeFakeRecords([]livedns.DomainRecord{record})...)
+}
+
+// deleteRecord removes a rrset, behind the provider's back.
+func (api *fakeGandiAPI) deleteRecord(fqdn, name, recordType string) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	api.zones[fqdn].removeRecord(name, recordType)
+}
+
+func (z *fakeZone) findRecord(name, recordType string) (int, bool) {
+	for i, r := range z.records {
+		if r.RrsetName == name && r.RrsetType == recordType {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func (z *fakeZone) removeRecord(name, recordType string) bool {
+	i, ok := z.findRecord(name, recordType)
+	if ok {
+		z.records = append(z.records[:i], z.records[i+1:]...)
+	}
+	return ok
+}
+
+// normalizeFakeRecords mimics the LiveDNS API which always returns
+// TXT values wrapped with quotes.
+func normalizeFakeRecords(records []livedns.DomainRecord) []livedns.DomainRecord {
+	var normalized []livedns.DomainRecord
+	for _, r := range records {
+		if r.RrsetTTL == 0 {
+			r.RrsetTTL = 10800
+		}
+		if r.RrsetType == TXT {
+			r.RrsetValues = wrapRecordsWithQuotes(r.RrsetValues)
+		}
+		normalized = append(normalized, r)
+	}
+	return normalized
+}
+
+func writeFakeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeFakeError(w http.ResponseWriter, status int, message string) {
+	writeFakeJSON(w, status, map[string]interface{}{
+		"code":    status,
+		"message": message,
+		"object":  "HTTPError",
+		"cause":   http.StatusText(status),
+	})
+}
+
+// writeFakeFieldErrors writes a validation error the way the Gandi
+// API does: without message, so that go-gandi keeps the field errors.
+func writeFakeFieldErrors(w http.ResponseWriter, fieldErrors []types.StandardError) {
+	writeFakeJSON(w, http.StatusBadRequest, types.StandardResponse{
+		Code:   http.StatusBadRequest,
+		Object: "HTTPBadRequest",
+		Cause:  http.StatusText(http.StatusBadRequest),
+		Errors: fieldErrors,
+	})
+}
+
+var fakePhoneRegexp = regexp.MustCompile(`^\+[0-9]{1,3}\.[0-9]+$`)
+
+func validateFakeContacts(req domain.CreateRequest) []types.StandardError {
+	var fieldErrors []types.StandardError
+	for _, contact := range []struct {
+		name    string
+		contact *domain.Contact
+	}{{"owner", req.Owner}, {"admin", req.Admin}, {"bill", req.Billing}, {"tech", req.Tech}} {
+		if contact.contact != nil && contact.contact.Phone != "" && !fakePhoneRegexp.MatchString(contact.contact.Phone) {
+			fieldErrors = append(fieldErrors, types.StandardError{
+				Location:    "body",
+				Name:        contact.name + ".phone",
+				Description: "Invalid phone number, the format is +<country code>.<number>",
+			})
+		}
+	}
+	return fieldErrors
+}
+
+func writeFakeMessage(w http.ResponseWriter, status int, message string) {
+	writeFakeJSON(w, status, map[string]string{"message": message})
+}
+
+func readFakeBody(r *http.Request, v interface{}) error {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	if len(body) == 0 {
+		return nil
+	}
+	return json.Unmarshal(body, v)
+}
+
+func (api *fakeGandiAPI) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	// The email client builds some URLs with a double slash
+	p := strings.TrimPrefix(path.Clean(r.URL.Path), "/v5/")
+	api.requests = append(api.requests, r.Method+" "+p)
+	if api.beforeRequest != nil {
+		api.beforeRequest(r)
+	}
+	if api.throttled > 0 {
+		api.throttled--
+		w.Header().Set("Retry-After", "0")
+		writeFakeError(w, http.StatusTooManyRequests, "Too many requests")
+		return
+	}
+
+	if r.Header.Get("Authorization") == "" {
+		writeFakeError(w, http.StatusUnauthorized, "Missing credentials")
+		return
+	}
+
+	parts := strings.Split(p, "/")
+	switch parts[0] {
+	case "domain":
+		api.serveDomain(w, r, parts[1:])
+	case "livedns":
+		api.serveLiveDNS(w, r, parts[1:])
+	case "email":
+		api.serveEmail(w, r, parts[1:])
+	case "simplehosting":
+		api.serveSimpleHosting(w, r, parts[1:])
+	case "certificate":
+		api.serveCertificate(w, r, parts[1:])
+	default:
+		writeFakeError(w, http.StatusNotFound, "The resource could not be found.")
+	}
+}
+
+func (api *fakeGandiAPI) createDomain(req domain.CreateRequest) *fakeDomain {
+	now := time.Now().UTC().Truncate(time.Second)
+	duration := req.Duration
+	if duration == 0 {
+		duration = 1
+	}
+	ends := now.AddDate(duration, 0, 0)
+	contacts := domain.Contacts{Owner: req.Owner, Admin: req.Admin, Billing: req.Billing, Tech: req.Tech}
+	if contacts.Admin == nil {
+		contacts.Admin = req.Owner
+	}
+	if contacts.Billing == nil {
+		contacts.Billing = req.Owner
+	}
+	if contacts.Tech == nil {
+		contacts.Tech = req.Owner
+	}
+	d := &fakeDomain{
+		details: domain.Details{
+			FQDN:        req.FQDN,
+			FQDNUnicode: req.FQDN,
+			ID:          api.newID(),
+			TLD:         req.FQDN[strings.LastIndex(req.FQDN, ".")+1:],
+			AutoRenew:   &domain.AutoRenew{Enabled: Bool(false)},
+			Contacts:    &contacts,
+			Dates:       &domain.ResponseDates{CreatedAt: &now, RegistryCreatedAt: &now, UpdatedAt: &now, RegistryEndsAt: &ends},
+			Nameservers: fakeLiveDNSNameservers,
+			Status:      []string{},
+			Tags:        []string{},
+		},
+		liveDNS: true,
+		hosts:   make(map[string]*domain.GlueRecord),
+	}
+	if len(req.Nameservers) > 0 {
+		d.details.Nameservers = req.Nameservers
+		d.liveDNS = false
+	}
+	api.domains[req.FQDN] = d
+	return d
+}
+
+// checkDomain answers the domain/check endpoint for a single domain
+func (api *fakeGandiAPI) checkDomain(w http.ResponseWriter, r *http.Request) {
+	fqdn := r.URL.Query().Get("name")
+	currency := r.URL.Query().Get("currency")
+	if currency == "" {
+		currency = "EUR"
+	}
+	price, ok := api.domainPrices[fqdn]
+	if !ok {
+		price = fakeDomainPrice
+	}
+	status := domainAvailable
+	if _, ok := api.domains[fqdn]; ok {
+		status = "unavailable"
+	}
+	writeFakeJSON(w, http.StatusOK, domainCheck{
+		Currency: currency,
+		Grid:     "A",
+		Products: []domainCheckProduct{{
+			Name:    fqdn,
+			Status:  status,
+			Process: "create",
+			Premium: api.premiumDomains[fqdn],
+			Prices: []domainCheckPrice{{
+				MinDuration:      1,
+				MaxDuration:      10,
+				DurationUnit:     "y",
+				PriceBeforeTaxes: price,
+				PriceAfterTaxes:  price * 1.2,
+			}},
+			Phases:          []domainCheckPhase{{Phase: "golive", Name: "General Availability"}},
+			ExtraParameters: api.tldExtraParameters[fqdn[strings.LastIndex(fqdn, ".")+1:]],
+		}},
+	})
+}
+
+// completeTransfer completes the transfer of a domain, which is then
+// in the account.
+func (api *fakeGandiAPI) completeTransfer(fqdn string) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	req := api.transferRequests[fqdn]
+	api.createDomain(domain.CreateRequest{
+		FQDN:        fqdn,
+		Owner:       req.Owner,
+		Admin:       req.Admin,
+		Billing:     req.Billing,
+		Tech:        req.Tech,
+		Nameservers: req.Nameservers,
+	})
+	transfer := api.transfers[fqdn]
+	transfer.Step = domainTransferDone
+	transfer.StepNumber = 5
+	for i := range transfer.FOA {
+		transfer.FOA[i].Answer = "approved"
+	}
+}
+
+// serveTransfer starts the transfers, which wait for the approval of
+// the owner until completeTransfer is called, and returns their status.
+func (api *fakeGandiAPI) serveTransfer(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) == 0 {
+		if r.Method != http.MethodPost {
+			writeFakeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		var req domainTransferRequest
+		if err := readFakeBody(r, &req); err != nil {
+			writeFakeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if _, ok := api.domains[req.FQDN]; ok {
+			writeFakeError(w, http.StatusConflict, "The domain is already in the account")
+			return
+		}
+		if req.AuthInfo == "" {
+			writeFakeFieldErrors(w, []types.StandardError{{Location: "body", Name: "authinfo", Description: "The authinfo is required"}})
+			return
+		}
+		if fieldErrors := validateFakeContacts(domain.CreateRequest{Owner: req.Owner, Admin: req.Admin, Billing: req.Billing, Tech: req.Tech}); len(fieldErrors) > 0 {
+			writeFakeFieldErrors(w, fieldErrors)
+			return
+		}
+		api.transferRequests[req.FQDN] = req
+		api.transfers[req.FQDN] = &domainTransfer{
+			FQDN:       req.FQDN,
+			Step:       "waiting_foa",
+			StepNumber: 2,
+			FOA:        []domainTransferFOA{{Email: req.Owner.Email, Answer: "pending"}},
+		}
+		writeFakeMessage(w, http.StatusAccepted, "The transfer has been started.")
+		return
+	}
+
+	transfer, ok := api.transfers[parts[0]]
+	if !ok {
+		writeFakeError(w, http.StatusNotFound, "The transfer could not be found.")
+		return
+	}
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		writeFakeJSON(w, http.StatusOK, transfer)
+	case len(parts) == 2 && parts[1] == "authinfo" && r.Method == http.MethodPost:
+		var req struct {
+			AuthInfo string `json:"authinfo"`
+		}
+		if err := readFakeBody(r, &req); err != nil {
+			writeFakeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		transferReq := api.transferRequests[parts[0]]
+		transferReq.AuthInfo = req.AuthInfo
+		api.transferRequests[parts[0]] = transferReq
+		transfer.Step = "waiting_foa"
+		transfer.ErrorType = ""
+		transfer.ErrorTypeLabel = ""
+		writeFakeMessage(w, http.StatusAccepted, "The transfer has been relaunched.")
+	default:
+		writeFakeError(w, http.StatusNotFound, "The resource could not be found.")
+	}
+}
+
+// serveOwnerChange submits the owner changes, which must be confirmed
+// by the previous and the new owners, and returns their status. The
+// change is done once its status has been read ownerChangePolls times.
+func (api *fakeGandiAPI) serveOwnerChange(w http.ResponseWriter, r *http.Request, fqdn string) {
+	d, ok := api.domains[fqdn]
+	if !ok {
+		writeFakeError(w, http.StatusNotFound, "The domain could not be found.")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		if api.ownerChangeForbidden {
+			writeFakeError(w, http.StatusForbidden, "Access was denied to this resource.")
+			return
+		}
+		change := d.ownerChange
+		if change == nil {
+			writeFakeError(w, http.StatusNotFound, "No owner change for this domain.")
+			return
+		}
+		if change.pending() {
+			if api.ownerChangePolls >= 0 && change.polls >= api.ownerChangePolls {
+				change.Status = ownerChangeDone
+				for email := range change.FOAStatus {
+					change.FOAStatus[email] = "done"
+				}
+				d.details.Contacts.Owner = change.Owner
+			}
+			change.polls++
+		}
+		writeFakeJSON(w, http.StatusOK, change.ownerChange)
+	case http.MethodPost:
+		var req struct {
+			Owner *domain.Contact `json:"owner"`
+		}
+		if err := readFakeBody(r, &req); err != nil {
+			writeFakeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if fieldErrors := validateFakeContacts(domain.CreateRequest{Owner: req.Owner}); len(fieldErrors) > 0 {
+			writeFakeFieldErrors(w, fieldErrors)
+			return
+		}
+		d.ownerChange = &fakeOwnerChange{ownerChange: ownerChange{
+			Owner:  req.Owner,
+			Status: "pending",
+			FOAStatus: map[string]string{
+				d.details.Contacts.Owner.Email: "pending",
+				req.Owner.Email:                "pending",
+			},
+		}}
+		writeFakeMessage(w, http.StatusAccepted, "The owner change has been submitted.")
+	default:
+		writeFakeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (api *fakeGandiAPI) serveDomain(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) == 1 && parts[0] == "check" && r.Method == http.MethodGet {
+		api.checkDomain(w, r)
+		return
+	}
+	if len(parts) > 0 && parts[0] == "transferin" {
+		api.serveTransfer(w, r, parts[1:])
+		return
+	}
+	if len(parts) == 2 && parts[0] == "changeowner" {
+		api.serveOwnerChange(w, r, parts[1])
+		return
+	}
+	if len(parts) == 0 || parts[0] != "domains" {
+		writeFakeError(w, http.StatusNotFound, "The resource could not be found.")
+		return
+	}
+	if len(parts) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			var names []string
+			for name := range api.domains {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			list := []domain.ListResponse{}
+			for _, name := range names {
+				d := api.domains[name].details
+				current := "other"
+				if api.domains[name].liveDNS {
+					current = "livedns"
+				}
+				list = append(list, domain.ListResponse{
+					FQDN:        d.FQDN,
+					FQDNUnicode: d.FQDNUnicode,
+					ID:          d.ID,
+					TLD:         d.TLD,
+					AutoRenew:   d.AutoRenew.Enabled,
+					Dates:       d.Dates,
+					NameServer:  &domain.NameServerConfig{Current: current},
+					Status:      d.Status,
+					Tags:        d.Tags,
+				})
+			}
+			writeFakeJSON(w, http.StatusOK, list)
+		case http.MethodPost:
+			var req domain.CreateRequest
+			if err := readFakeBody(r, &req); err != nil {
+				writeFakeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			if _, ok := api.domains[req.FQDN]; ok {
+				writeFakeError(w, http.StatusConflict, "The domain is not available")
+				return
+			}
+			if api.premiumDomains[req.FQDN] && !req.EnforcePremium {
+				writeFakeError(w, http.StatusBadRequest, "The domain is premium")
+				return
+			}
+			if fieldErrors := validateFakeContacts(req); len(fieldErrors) > 0 {
+				writeFakeFieldErrors(w, fieldErrors)
+				return
+			}
+			api.createDomain(req)
+			writeFakeMessage(w, http.StatusAccepted, "Domain Created.")
+		default:
+			writeFakeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	d, ok := api.domains[parts[1]]
+	if !ok {
+		writeFakeError(w, http.StatusNotFound, "The domain could not be found.")
+		return
+	}
+	if len(parts) == 2 {
+		switch {
+		case r.Method == http.MethodGet:
+			writeFakeJSON(w, http.StatusOK, d.details)
+		case r.Method == http.MethodDelete && api.releasableTLDs[d.details.TLD]:
+			delete(api.domains, parts[1])
+			writeFakeMessage(w, http.StatusAccepted, "The domain has been deleted.")
+		case r.Method == http.MethodDelete && api.releaseErrors[d.details.TLD] != 0:
+			writeFakeError(w, api.releaseErrors[d.details.TLD], "The domain can't be deleted")
+		case r.Method == http.MethodDelete:
+			writeFakeError(w, http.StatusForbidden, "The registry does not allow to delete this domain")
+		default:
+			writeFakeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	switch parts[2] {
+	case "nameservers":
+		switch r.Method {
+		case http.MethodGet:
+			writeFakeJSON(w, http.StatusOK, d.details.Nameservers)
+		case http.MethodPut:
+			var req domain.Nameservers
+			if err := readFakeBody(r, &req); err != nil {
+				writeFakeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			d.details.Nameservers = req.Nameservers
+			d.liveDNS = false
+			writeFakeMessage(w, http.StatusAccepted, "Nameservers updated.")
+		default:
+			writeFakeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	case "livedns":
+		switch r.Method {
+		case http.MethodGet:
+			current := "other"
+			if d.liveDNS {
+				current = "livedns"
+			}
+			writeFakeJSON(w, http.StatusOK, domain.LiveDNS{Current: current, Nameservers: fakeLiveDNSNameservers})
+		case http.MethodPost:
+			d.liveDNS = true
+			d.details.Nameservers = fakeLiveDNSNameservers
+			writeFakeMessage(w, http.StatusAccepted, "LiveDNS enabled.")
+		default:
+			writeFakeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	case "contacts":
+		switch r.Method {
+		case http.MethodGet:
+			writeFakeJSON(w, http.StatusOK, d.details.Contacts)
+		case http.MethodPatch:
+			var req domain.Contacts
+			if err := readFakeBody(r, &req); err != nil {
+				writeFakeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			if req.Admin != nil {
+				d.details.Contacts.Admin = req.Admin
+			}
+			if req.Billing != nil {
+				d.details.Contacts.Billing = req.Billing
+			}
+			if req.Tech != nil {
+				d.details.Contacts.Tech = req.Tech
+			}
+			writeFakeMessage(w, http.StatusAccepted, "Contacts updated.")
+		default:
+			writeFakeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	case "autorenew":
+		var req domain.AutoRenew
+		if err := readFakeBody(r, &req); err != nil {
+			writeFakeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		d.details.AutoRenew.Enabled = req.Enabled
+		writeFakeMessage(w, http.StatusAccepted, "Autorenew updated.")
+	case "status":
+		if r.Method != http.MethodPatch {
+			writeFakeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		var req map[string]bool
+		if err := readFakeBody(r, &req); err != nil {
+			writeFakeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		for status, enabled := range req {
+			statuses := []string{}
+			for _, s := range d.details.Status {
+				if s != status {
+					statuses = append(statuses, s)
+				}
+			}
+			if enabled {
+				statuses = append(statuses, status)
+			}
+			d.details.Status = statuses
+		}
+		writeFakeMessage(w, http.StatusAccepted, "The status has been updated.")
+	case "authinfo":
+		if r.Method != http.MethodPatch {
+			writeFakeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		expires := time.Now().UTC().Truncate(time.Second).AddDate(0, 0, 30)
+		d.details.AuthInfo = "authinfo-" + api.newID()[24:]
+		d.details.Dates.AuthInfoExpiresAt = &expires
+		writeFakeMessage(w, http.StatusAccepted, "The authinfo has been reset.")
+	case "renew":
+		if r.Method != http.MethodPost {
+			writeFakeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		var req struct {
+			Duration int `json:"duration"`
+		}
+		if err := readFakeBody(r, &req); err != nil {
+			writeFakeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		ends := d.details.Dates.RegistryEndsAt.AddDate(req.Duration, 0, 0)
+		if ends.After(time.Now().AddDate(10, 0, 0)) {
+			writeFakeFieldErrors(w, []types.StandardError{{Location: "body", Name: "duration", Description: "The domain can't be registered for more than 10 years"}})
+			return
+		}
+		if !api.pendingRenewals {
+			d.details.Dates.RegistryEndsAt = &ends
+		}
+		writeFakeMessage(w, http.StatusAccepted, "The domain has been renewed.")
+	case "tags":
+		switch r.Method {
+		case http.MethodGet:
+			writeFakeJSON(w, http.StatusOK, d.details.Tags)
+		case http.MethodPut:
+			var req domain.Tags
+			if err := readFakeBody(r, &req); err != nil {
+				writeFakeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			d.details.Tags = req.Tags
+			writeFakeMessage(w, http.StatusOK, "Tags updated.")
+		case http.MethodDelete:
+			d.details.Tags = []string{}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeFakeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	case "dnskeys":
+		api.serveDNSSECKeys(w, r, d, parts[3:])
+	case "hosts":
+		api.serveGlueRecords(w, r, d, parts[3:])
+	default:
+		writeFakeError(w, http.StatusNotFound, "The resource could not be found.")
+	}
+}
+
+func (api *fakeGandiAPI) serveDNSSECKeys(w http.ResponseWriter, r *http.Request, d *fakeDomain, parts []string) {
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodGet:
+		keys := append([]domain.DNSSECKey{}, d.dnssecKeys...)
+		writeFakeJSON(w, http.StatusOK, keys)
+	case len(parts) == 0 && r.Method == http.MethodPost:
+		var req domain.DNSSECKeyCreateRequest
+		if err := readFakeBody(r, &req); err != nil {
+			writeFakeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		api.nextID++
+		d.dnssecKeys = append(d.dnssecKeys, domain.DNSSECKey{
+			ID:        api.nextID,
+			Algorithm: req.Algorithm,
+			Type:      req.Type,
+			PublicKey: req.PublicKey,
+			KeyTag:    api.nextID,
+		})
+		writeFakeMessage(w, http.StatusCreated, "DNSSEC key created.")
+	case len(parts) == 1 && r.Method == http.MethodDelete:
+		for i, k := range d.dnssecKeys {
+			if strconv.Itoa(k.ID) == parts[0] {
+				d.dnssecKeys = append(d.dnssecKeys[:i], d.dnssecKeys[i+1:]...)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		writeFakeError(w, http.StatusNotFound, "The DNSSEC key could not be found.")
+	default:
+		writeFakeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (api *fakeGandiAPI) serveGlueRecords(w http.ResponseWriter, r *http.Request, d *fakeDomain, parts []string) {
+	if len(parts) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			var names []string
+			for name := range d.hosts {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			hosts := []domain.GlueRecord{}
+			for _, name := range names {
+				hosts = append(hosts, *d.hosts[name])
+			}
+			writeFakeJSON(w, http.StatusOK, hosts)
+		case http.MethodPost:
+			var req domain.GlueRecordCreateRequest
+			if err := readFakeBody(r, &req); err != nil {
+				writeFakeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			fqdn := req.Name + "." + d.details.FQDN
+			d.hosts[req.Name] = &domain.GlueRecord{
+				Name:        req.Name,
+				IPs:         req.IPs,
+				FQDN:        fqdn,
+				FQDNUnicode: fqdn,
+				Href:        api.server.URL + "/v5/domain/domains/" + d.details.FQDN + "/hosts/" + req.Name,
+			}
+			writeFakeMessage(w, http.StatusAccepted, "Glue record created.")
+		default:
+			writeFakeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+	host, ok := d.hosts[parts[0]]
+	if !ok {
+		writeFakeError(w, http.StatusNotFound, "The glue record could not be found.")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeFakeJSON(w, http.StatusOK, host)
+	case http.MethodPut:
+		var req domain.GlueRecordUpdateRequest
+		if err := readFakeBody(r, &req); err != nil {
+			writeFakeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		host.IPs = req.IPs
+		writeFakeMessage(w, http.StatusAccepted, "Glue record updated.")
+	case http.MethodDelete:
+		delete(d.hosts, parts[0])
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeFakeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (api *fakeGandiAPI) serveLiveDNS(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) == 0 || parts[0] != "domains" {
+		writeFakeError(w, http.StatusNotFound, "The resource could not be found.")
+		return
+	}
+	if len(parts) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			var names []string
+			for name := range api.zones {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			list := []livedns.Domain{}
+			for _, name := range names {
+				list = append(list, api.zones[name].domain)
+			}
+			writeFakeJSON(w, http.StatusOK, list)
+		case http.MethodPost:
+			var req struct {
+				FQDN string `json:"fqdn"`
+			}
+			if err := readFakeBody(r, &req); err != nil {
+				writeFakeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			if _, ok := api.zones[req.FQDN]; ok {
+				writeFakeError(w, http.StatusConflict, "The domain already exists")
+				return
+			}
+			api.zones[req.FQDN] = &fakeZone{domain: livedns.Domain{FQDN: req.FQDN, AutomaticSnapshots: Bool(true)}}
+			writeFakeMessage(w, http.StatusCreated, "The domain has been created")
+		default:
+			writeFakeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	zone, ok := api.zones[parts[1]]
+	if !ok {
+		writeFakeError(w, http.StatusNotFound, "The domain could not be found.")
+		return
+	}
+	if len(parts) == 2 {
+		switch r.Method {
+		case http.MethodGet:
+			writeFakeJSON(w, http.StatusOK, zone.domain)
+		case http.MethodPatch:
+			var req livedns.UpdateDomainRequest
+			if err := readFakeBody(r, &req); err != nil {
+				writeFakeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			if req.AutomaticSnapshots != nil {
+				zone.domain.AutomaticSnapshots = req.AutomaticSnapshots
+			}
+			writeFakeMessage(w, http.StatusOK, "Domain updated")
+		default:
+			writeFakeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	switch parts[2] {
+	case "nameservers":
+		writeFakeJSON(w, http.StatusOK, fakeLiveDNSNameservers)
+	case "records":
+		api.serveRecords(w, r, zone, parts[3:])
+	case "snapshots":
+		api.serveSnapshots(w, r, zone, parts[3:])
+	default:
+		writeFakeError(w, http.StatusNotFound, "The resource could not be found.")
+	}
+}
+
+func (api *fakeGandiAPI) serveRecords(w http.ResponseWriter, r *http.Request, zone *fakeZone, parts []string) {
+	var matching []livedns.DomainRecord
+	for _, rec := range zone.records {
+		if (len(parts) < 1 || rec.RrsetName == parts[0]) && (len(parts) < 2 || rec.RrsetType == parts[1]) {
+			rec.RrsetHref = api.server.URL + "/v5/livedns/domains/" + zone.domain.FQDN + "/records/" + rec.RrsetName + "/" + rec.RrsetType
+			matching = append(matching, rec)
+		}
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if len(parts) == 0 && r.Header.Get("Accept") == "text/plain" {
+			w.Header().Set("Content-Type", "text/plain")
+			for _, rec := range matching {
+				for _, v := range rec.RrsetValues {
+					fmt.Fprintf(w, "%s %d IN %s %s\n", rec.RrsetName, rec.RrsetTTL, rec.RrsetType, v)
+				}
+			}
+			return
+		}
+		if len(parts) == 2 {
+			if len(matching) == 0 {
+				writeFakeError(w, http.StatusNotFound, "The resource could not be found.")
+				return
+			}
+			writeFakeJSON(w, http.StatusOK, matching[0])
+			return
+		}
+		if matching == nil {
+			matching = []livedns.DomainRecord{}
+		}
+		writeFakeJSON(w, http.StatusOK, matching)
+	case http.MethodPost:
+		if len(parts) != 0 {
+			writeFakeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		var rec livedns.DomainRecord
+		if err := readFakeBody(r, &rec); err != nil {
+			writeFakeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if _, exists := zone.findRecord(rec.RrsetName, rec.RrsetType); exists {
+			writeFakeError(w, http.StatusConflict, "A record with that name / type pair already exists")
+			return
+		}
+		zone.records = append(zone.records, normalizeFakeRecords([]livedns.DomainRecord{rec})...)
+		writeFakeMessage(w, http.StatusCreated, "DNS Record Created")
+	case http.MethodPut:
+		if len(parts) == 2 {
+			var rec livedns.DomainRecord
+			if err := readFakeBody(r, &rec); err != nil {
+				writeFakeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			rec.RrsetName = parts[0]
+			rec.RrsetType = parts[1]
+			zone.removeRecord(parts[0], parts[1])
+			zone.records = append(zone.records, normalizeFakeRecords([]livedns.DomainRecord{rec})...)
+			writeFakeMessage(w, http.StatusCreated, "DNS Record Created")
+			return
+		}
+		var req struct {
+			Items []livedns.DomainRecord `json:"items"`
+		}
+		if err := readFakeBody(r, &req); err != nil {
+			writeFakeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		var kept []livedns.DomainRecord
+		for _, rec := range zone.records {
+			if len(parts) == 1 && rec.RrsetName != parts[0] {
+				kept = append(kept, rec)
+			}
+		}
+		for i := range req.Items {
+			if len(parts) == 1 {
+				req.Items[i].RrsetName = parts[0]
+			}
+		}
+		zone.records = append(kept, normalizeFakeRecords(req.Items)...)
+		writeFakeMessage(w, http.StatusCreated, "DNS Records Created")
+	case http.MethodDelete:
+		if len(parts) == 2 && len(matching) == 0 {
+			writeFakeError(w, http.StatusNotFound, "The resource could not be found.")
+			return
+		}
+		var kept []livedns.DomainRecord
+		for _, rec := range zone.records {
+			if (len(parts) >= 1 && rec.RrsetName != parts[0]) || (len(parts) == 2 && rec.RrsetType != parts[1]) {
+				kept = append(kept, rec)
+			}
+		}
+		zone.records = kept
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeFakeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (api *fakeGandiAPI) serveSnapshots(w http.ResponseWriter, r *http.Request, zone *fakeZone, parts []string) {
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodGet:
+		snapshots := []livedns.Snapshot{}
+		for _, s := range zone.snapshots {
+			s.ZoneData = nil
+			snapshots = append(snapshots, s)
+		}
+		writeFakeJSON(w, http.StatusOK, snapshots)
+	case len(parts) == 0 && r.Method == http.MethodPost:
+		var req struct {
+			Name string `json:"name"`
+		}
+		if err := readFakeBody(r, &req); err != nil {
+			writeFakeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		id := api.newID()
+		zone.snapshots = append(zone.snapshots, livedns.Snapshot{
+			ID:           id,
+			Name:         req.Name,
+			Automatic:    Bool(false),
+			CreatedAt:    time.Now().UTC().Truncate(time.Second),
+			SnapshotHREF: api.server.URL + "/v5/livedns/domains/" + zone.domain.FQDN + "/snapshots/" + id,
+			ZoneData:     append([]livedns.DomainRecord{}, zone.records...),
+		})
+		writeFakeJSON(w, http.StatusCreated, map[string]string{"message": "Snapshot Created", "id": id})
+	case len(parts) == 1:
+		for i, s := range zone.snapshots {
+			if s.ID != parts[0] {
+				continue
+			}
+			switch r.Method {
+			case http.MethodGet:
+				writeFakeJSON(w, http.StatusOK, s)
+			case http.MethodDelete:
+				zone.snapshots = append(zone.snapshots[:i], zone.snapshots[i+1:]...)
+				w.WriteHeader(http.StatusNoContent)
+			default:
+				writeFakeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			}
+			return
+		}
+		writeFakeError(w, http.StatusNotFound, "The snapshot could not be found.")
+	default:
+		writeFakeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (api *fakeGandiAPI) serveEmail(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) < 2 {
+		writeFakeError(w, http.StatusNotFound, "The resource could not be found.")
+		return
+	}
+	fqdn := parts[1]
+	switch parts[0] {
+	case "mailboxes":
+		boxes := api.mailboxes[fqdn]
+		if boxes == nil {
+			boxes = make(map[string]*email.MailboxResponse)
+			api.mailboxes[fqdn] = boxes
+		}
+		if len(parts) == 2 {
+			switch r.Method {
+			case http.MethodGet:
+				var ids []string
+				for id := range boxes {
+					ids = append(ids, id)
+				}
+				sort.Strings(ids)
+				list := []email.ListMailboxResponse{}
+				for _, id := range ids {
+					b := boxes[id]
+					list = append(list, email.ListMailboxResponse{
+						Address: b.Address, Domain: b.Domain, Href: b.Href, ID: b.ID,
+						Login: b.Login, MailboxType: b.MailboxType, QuotaUsed: b.QuotaUsed,
+					})
+				}
+				writeFakeJSON(w, http.StatusOK, list)
+			case http.MethodPost:
+				var req email.CreateEmailRequest
+				if err := readFakeBody(r, &req); err != nil {
+					writeFakeError(w, http.StatusBadRequest, err.Error())
+					return
+				}
+				id := api.newID()
+				boxes[id] = &email.MailboxResponse{
+					ID:          id,
+					Address:     req.Login + "@" + fqdn,
+					Aliases:     req.Aliases,
+					Domain:      fqdn,
+					Href:        api.server.URL + "/v5/email/mailboxes/" + fqdn + "/" + id,
+					Login:       req.Login,
+					MailboxType: req.MailboxType,
+				}
+				writeFakeMessage(w, http.StatusAccepted, "The mailbox is being created.")
+			default:
+				writeFakeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			}
+			return
+		}
+		box, ok := boxes[parts[2]]
+		if !ok {
+			writeFakeError(w, http.StatusNotFound, "The mailbox could not be found.")
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeFakeJSON(w, http.StatusOK, box)
+		case http.MethodPatch:
+			var req email.UpdateEmailRequest
+			if err := readFakeBody(r, &req); err != nil {
+				writeFakeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			if req.Login != "" {
+				box.Login = req.Login
+				box.Address = req.Login + "@" + fqdn
+			}
+			box.Aliases = req.Aliases
+			writeFakeMessage(w, http.StatusAccepted, "The mailbox is being updated.")
+		case http.MethodDelete:
+			delete(boxes, parts[2])
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeFakeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	case "forwards":
+		forwards := api.forwards[fqdn]
+		if forwards == nil {
+			forwards = make(map[string]*email.GetForwardRequest)
+			api.forwards[fqdn] = forwards
+		}
+		if len(parts) == 2 {
+			switch r.Method {
+			case http.MethodGet:
+				var sources []string
+				for source := range forwards {
+					sources = append(sources, source)
+				}
+				sort.Strings(sources)
+				list := []email.GetForwardRequest{}
+				for _, source := range sources {
+					list = append(list, *forwards[source])
+				}
+				writeFakeJSON(w, http.StatusOK, list)
+			case http.MethodPost:
+				var req email.CreateForwardRequest
+				if err := readFakeBody(r, &req); err != nil {
+					writeFakeError(w, http.StatusBadRequest, err.Error())
+					return
+				}
+				forwards[req.Source] = &email.GetForwardRequest{
+					Source:       req.Source,
+					Destinations: req.Destinations,
+					Href:         api.server.URL + "/v5/email/forwards/" + fqdn + "/" + req.Source,
+				}
+				writeFakeMessage(w, http.StatusCreated, "The email forwarding has been created.")
+			default:
+				writeFakeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			}
+			return
+		}
+		forward, ok := forwards[parts[2]]
+		if !ok {
+			writeFakeError(w, http.StatusNotFound, "The forwarding could not be found.")
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			var req email.UpdateForwardRequest
+			if err := readFakeBody(r, &req); err != nil {
+				writeFakeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			forward.Destinations = req.Destinations
+			writeFakeMessage(w, http.StatusOK, "The email forwarding has been updated.")
+		case http.MethodDelete:
+			delete(forwards, parts[2])
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeFakeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	default:
+		writeFakeError(w, http.StatusNotFound, "The resource could not be found.")
+	}
+}
+
+func (api *fakeGandiAPI) serveSimpleHosting(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) == 0 || parts[0] != "instances" {
+		writeFakeError(w, http.StatusNotFound, "The resource could not be found.")
+		return
+	}
+	if len(parts) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			var ids []string
+			for id := range api.instances {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+			list := []simplehosting.Instance{}
+			for _, id := range ids {
+				list = append(list, *api.instances[id])
+			}
+			writeFakeJSON(w, http.StatusOK, list)
+		case http.MethodPost:
+			var req simplehosting.CreateInstanceRequest
+			if err := readFakeBody(r, &req); err != nil {
+				writeFakeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			id := api.newID()
+			api.instances[id] = &simplehosting.Instance{
+				ID:         id,
+				Name:       req.Name,
+				Size:       req.Size,
+				Status:     "active",
+				Database:   req.Type.Database,
+				Language:   req.Type.Language,
+				Datacenter: &simplehosting.Datacenter{Code: req.Location + "-01", Region: req.Location},
+			}
+			api.vhosts[id] = make(map[string]*simplehosting.Vhost)
+			w.Header().Set("Content-Location", api.server.URL+"/v5/simplehosting/instances/"+id)
+			writeFakeMessage(w, http.StatusAccepted, "The instance is being created.")
+		default:
+			writeFakeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	instance, ok := api.instances[parts[1]]
+	if !ok {
+		writeFakeError(w, http.StatusNotFound, "The instance could not be found.")
+		return
+	}
+	if len(parts) == 2 {
+		switch r.Method {
+		case http.MethodGet:
+			writeFakeJSON(w, http.StatusOK, instance)
+		case http.MethodDelete:
+			delete(api.instances, parts[1])
+			delete(api.vhosts, parts[1])
+			writeFakeMessage(w, http.StatusAccepted, "The instance is being deleted.")
+		default:
+			writeFakeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+	if parts[2] != "vhosts" {
+		writeFakeError(w, http.StatusNotFound, "The resource could not be found.")
+		return
+	}
+	vhosts := api.vhosts[parts[1]]
+	if len(parts) == 3 {
+		switch r.Method {
+		case http.MethodGet:
+			var names []string
+			for name := range vhosts {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			list := []simplehosting.Vhost{}
+			for _, name := range names {
+				list = append(list, *vhosts[name])
+			}
+			writeFakeJSON(w, http.StatusOK, list)
+		case http.MethodPost:
+			var req simplehosting.CreateVhostRequest
+			if err := readFakeBody(r, &req); err != nil {
+				writeFakeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			vhost := &simplehosting.Vhost{
+				FQDN:          req.FQDN,
+				Status:        "active",
+				CreatedAt:     time.Now().UTC().Format(time.RFC3339),
+				LinkedDNSZone: &simplehosting.LinkedDNSZone{},
+				Application:   req.Application,
+			}
+			if req.LinkedDNSZone != nil {
+				vhost.LinkedDNSZone.AllowAlteration = req.LinkedDNSZone.AllowAlteration
+			}
+			vhosts[req.FQDN] = vhost
+			writeFakeJSON(w, http.StatusAccepted, vhost)
+		default:
+			writeFakeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+	vhost, ok := vhosts[parts[3]]
+	if !ok {
+		writeFakeError(w, http.StatusNotFound, "The vhost could not be found.")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeFakeJSON(w, http.StatusOK, vhost)
+	case http.MethodPatch:
+		var req simplehosting.PatchVhostRequest
+		if err := readFakeBody(r, &req); err != nil {
+			writeFakeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if req.Application != nil {
+			vhost.Application = req.Application
+		}
+		writeFakeJSON(w, http.StatusAccepted, simplehosting.PatchVhostResponse{FQDN: vhost.FQDN, Status: vhost.Status})
+	case http.MethodDelete:
+		delete(vhosts, parts[3])
+		writeFakeMessage(w, http.StatusAccepted, "The vhost is being deleted.")
+	default:
+		writeFakeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (api *fakeGandiAPI) serveCertificate(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) == 0 || parts[0] != "issued-certs" {
+		writeFakeError(w, http.StatusNotFound, "The resource could not be found.")
+		return
+	}
+	if len(parts) == 1 {
+		if r.Method != http.MethodPost {
+			writeFakeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		var req certificate.CreateCertificateRequest
+		if err := readFakeBody(r, &req); err != nil {
+			writeFakeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		id := api.newID()
+		api.certificates[id] = &certificate.CertificateType{
+			ID:      id,
+			CN:      req.CN,
+			Status:  "pending",
+			Package: &certificate.Package{Name: req.Package},
+		}
+		writeFakeJSON(w, http.StatusAccepted, certificate.CreateCertificateResponse{
+			ID:      id,
+			Href:    api.server.URL + "/v5/certificate/issued-certs/" + id,
+			Message: "The certificate is being created.",
+		})
+		return
+	}
+	cert, ok := api.certificates[parts[1]]
+	if !ok {
+		writeFakeError(w, http.StatusNotFound, "The certificate could not be found.")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeFakeJSON(w, http.StatusOK, cert)
+	case http.MethodDelete:
+		delete(api.certificates, parts[1])
+		writeFakeMessage(w, http.StatusAccepted, "The certificate is being revoked.")
+	default:
+		writeFakeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
@@ -0,0 +1,525 @@
+package gandi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-gandi/go-gandi/domain"
+	"github.com/go-gandi/go-gandi/email"
+	"github.com/go-gandi/go-gandi/simplehosting"
+	"github.com/go-gandi/go-gandi/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// ImportConfigOptions are the options of GenerateImportConfig
+type ImportConfigOptions struct {
+	// Domains restricts the generated configuration to these
+	// domains. All the domains of the account are used when empty,
+	// along with the SimpleHosting instances.
+	Domains []string
+}
+
+// GenerateImportConfig writes the configuration of the resources of
+// the account along with the import blocks importing them, as used by
+// Terraform >= 1.5. The provider is configured from its environment
+// variables, such as GANDI_PERSONAL_ACCESS_TOKEN and GANDI_URL.
+func GenerateImportConfig(ctx context.Context, w io.Writer, options ImportConfigOptions) error {
+	provider := Provider()
+	diags := provider.Configure(ctx, terraform.NewResourceConfigRaw(map[string]interface{}{}))
+	for _, d := range diags {
+		log.Printf("[WARN] %s: %s", d.Summary, d.Detail)
+	}
+	if diags.HasError() {
+		return fmt.Errorf("failed to configure the provider: %s", diags[0].Summary)
+	}
+	return generateImportConfig(ctx, provider.Meta().(*clients), w, options)
+}
+
+// importConfigGenerator writes the resource and import blocks, with
+// unique resource names
+type importConfigGenerator struct {
+	ctx     context.Context
+	clients *clients
+	w       io.Writer
+	names   map[string]bool
+	err     error
+}
+
+func generateImportConfig(ctx context.Context, c *clients, w io.Writer, options ImportConfigOptions) error {
+	g := &importConfigGenerator{ctx: ctx, clients: c, w: w, names: make(map[string]bool)}
+
+	fqdns := options.Domains
+	if len(fqdns) == 0 {
+		var domains []domain.ListResponse
+		err := c.call(ctx, func() (err error) {
+			domains, err = c.Domain.ListDomains()
+			return
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list the domains: %w", err)
+		}
+		for _, d := range domains {
+			fqdns = append(fqdns, d.FQDN)
+		}
+		sort.Strings(fqdns)
+	}
+
+	g.comment("Generated by terraform-provider-gandi generate-imports.")
+	g.comment("The passwords of the mailboxes can't be read from the Gandi API: they")
+	g.comment("are ignored until they are set.")
+	for _, fqdn := range fqdns {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := g.domain(fqdn); err != nil {
+			return err
+		}
+	}
+	if len(options.Domains) == 0 {
+		g.simpleHostingInstances()
+	}
+	return g.err
+}
+
+// resourceNameReplacer replaces the characters which are not allowed
+// in the names of the resources
+var resourceNameReplacer = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// resourceName returns a unique name for a resource of resourceType,
+// made of parts
+func (g *importConfigGenerator) resourceName(resourceType string, parts ...string) string {
+	base := strings.Trim(resourceNameReplacer.ReplaceAllString(strings.ToLower(strings.Join(parts, "_")), "_"), "_")
+	if base == "" || (base[0] >= '0' && base[0] <= '9') {
+		base = "r_" + base
+	}
+	name := base
+	for i := 2; g.names[resourceType+"."+name]; i++ {
+		name = base + "_" + strconv.Itoa(i)
+	}
+	g.names[resourceType+"."+name] = true
+	return name
+}
+
+func (g *importConfigGenerator) comment(format string, args ...interface{}) {
+	g.write("# " + fmt.Sprintf(format, args...) + "\n")
+}
+
+// warn writes the failure to read some resources, without stopping
+// the generation of the other ones
+func (g *importConfigGenerator) warn(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	log.Printf("[WARN] %s", message)
+	g.write("\n")
+	g.comment("%s", message)
+}
+
+func (g *importConfigGenerator) write(s string) {
+	if g.err == nil {
+		_, g.err = io.WriteString(g.w, s)
+	}
+}
+
+// resource writes the import block of the resource followed by its
+// resource block
+func (g *importConfigGenerator) resource(resourceType, id string, block *hclBlock, name ...string) {
+	resourceName := g.resourceName(resourceType, name...)
+	block.header = fmt.Sprintf("resource %s %s", hclString(resourceType), hclString(resourceName))
+
+	importBlock := &hclBlock{header: "import"}
+	importBlock.attribute("to", hclExpression(resourceType+"."+resourceName))
+	importBlock.attribute("id", id)
+
+	g.write("\n")
+	g.writeBlock(importBlock)
+	g.write("\n")
+	g.writeBlock(block)
+}
+
+// writeBlock writes the block, or keeps the error if one of its values
+// can't be written in HCL
+func (g *importConfigGenerator) writeBlock(block *hclBlock) {
+	s, err := block.render()
+	if err != nil {
+		if g.err == nil {
+			g.err = fmt.Errorf("failed to write %s: %w", block.header, err)
+		}
+		return
+	}
+	g.write(s)
+}
+
+// notFound returns whether err is a 404 error of the Gandi API, as
+// returned for the services which are not enabled on a domain
+func notFound(err error) bool {
+	requestError, ok := err.(*types.RequestError)
+	return ok && requestError.StatusCode == 404
+}
+
+func (g *importConfigGenerator) domain(fqdn string) error {
+	cache := g.clients.Cache
+	details, err := cache.domainDetails(g.ctx, fqdn)
+	if err != nil {
+		return fmt.Errorf("failed to get the domain %s: %w", fqdn, err)
+	}
+	livedns, err := cache.domainLiveDNS(g.ctx, fqdn)
+	if err != nil {
+		return fmt.Errorf("failed to get the nameservers of %s: %w", fqdn, err)
+	}
+
+	block := &hclBlock{}
+	block.attribute("name", details.FQDN)
+	if details.AutoRenew != nil && details.AutoRenew.Enabled != nil {
+		block.attribute("autorenew", *details.AutoRenew.Enabled)
+	}
+	if len(details.Tags) > 0 {
+		block.attribute("tags", details.Tags)
+	}
+	if (details.CanTLDLock == nil || *details.CanTLDLock) && !transferLocked(details) {
+		block.attribute("transfer_lock", false)
+	}
+	if details.Contacts != nil && details.Contacts.Owner != nil {
+		block.contact("owner", details.Contacts.Owner)
+	}
+	if livedns.Current != "livedns" {
+		// The external nameservers are managed by a gandi_nameservers
+		// resource, instead of the deprecated nameservers attribute
+		block.block("lifecycle").attribute("ignore_changes", hclExpression("[nameservers]"))
+	}
+	g.resource("gandi_domain", fqdn, block, fqdn)
+
+	if livedns.Current != "livedns" {
+		nameservers := &hclBlock{}
+		nameservers.attribute("domain", fqdn)
+		nameservers.attribute("nameservers", details.Nameservers)
+		g.resource("gandi_nameservers", fqdn, nameservers, fqdn)
+	}
+
+	if livedns.Current == "livedns" {
+		g.records(fqdn)
+	}
+	g.glueRecords(fqdn)
+	g.dnssecKeys(fqdn)
+	g.mailboxes(fqdn)
+	g.forwards(fqdn)
+	return nil
+}
+
+// contact adds the block of a contact of a domain, without the
+// attributes left to their default value
+func (b *hclBlock) contact(name string, contact *domain.Contact) {
+	c := *contact
+	if c.DataObfuscated == nil {
+		c.DataObfuscated = Bool(false)
+	}
+	if c.MailObfuscated == nil {
+		c.MailObfuscated = Bool(false)
+	}
+	block := b.block(name)
+	attributes := flattenContact(&c)[0].(map[string]interface{})
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		switch value := attributes[key].(type) {
+		case string:
+			if value != "" {
+				block.attribute(key, value)
+			}
+		case bool:
+			if value {
+				block.attribute(key, value)
+			}
+		case map[string]interface{}:
+			if len(value) > 0 {
+				block.attribute(key, value)
+			}
+		}
+	}
+}
+
+func (g *importConfigGenerator) records(zone string) {
+	records, err := getZoneRecords(g.ctx, g.clients.Cache, zone, true)
+	if err != nil {
+		if !notFound(err) {
+			g.warn("failed to list the LiveDNS records of %s: %s", zone, err)
+		}
+		return
+	}
+	for _, r := range records {
+		name := r.RrsetName
+		switch name {
+		case "@":
+			name = "apex"
+		default:
+			name = strings.ReplaceAll(name, "*", "wildcard")
+		}
+		block := &hclBlock{}
+		block.attribute("zone", zone)
+		block.attribute("name", r.RrsetName)
+		block.attribute("type", r.RrsetType)
+		block.attribute("ttl", r.RrsetTTL)
+		block.attribute("values", r.RrsetValues)
+		g.resource("gandi_livedns_record", zone+"/"+r.RrsetName+"/"+r.RrsetType, block, zone, name, r.RrsetType)
+	}
+}
+
+func (g *importConfigGenerator) glueRecords(zone string) {
+	var glueRecords []domain.GlueRecord
+	err := g.clients.call(g.ctx, func() (err error) {
+		glueRecords, err = g.clients.Domain.ListGlueRecords(zone)
+		return
+	})
+	if err != nil {
+		if !notFound(err) {
+			g.warn("failed to list the glue records of %s: %s", zone, err)
+		}
+		return
+	}
+	for _, r := range glueRecords {
+		block := &hclBlock{}
+		block.attribute("zone", zone)
+		block.attribute("name", r.Name)
+		block.attribute("ips", r.IPs)
+		g.resource("gandi_glue_record", zone+"/"+r.Name, block, zone, r.Name)
+	}
+}
+
+func (g *importConfigGenerator) dnssecKeys(fqdn string) {
+	var keys []domain.DNSSECKey
+	err := g.clients.call(g.ctx, func() (err error) {
+		keys, err = g.clients.Domain.ListDNSSECKeys(fqdn)
+		return
+	})
+	if err != nil {
+		if !notFound(err) {
+			g.warn("failed to list the DNSSEC keys of %s: %s", fqdn, err)
+		}
+		return
+	}
+	for _, k := range keys {
+		block := &hclBlock{}
+		block.attribute("domain", fqdn)
+		block.attribute("algorithm", k.Algorithm)
+		block.attribute("type", k.Type)
+		block.attribute("public_key", k.PublicKey)
+		id := strconv.Itoa(k.ID)
+		g.resource("gandi_dnssec_key", fqdn+"/"+id, block, fqdn, id)
+	}
+}
+
+func (g *importConfigGenerator) mailboxes(fqdn string) {
+	client := g.clients.Email
+	var mailboxes []email.ListMailboxResponse
+	err := g.clients.call(g.ctx, func() (err error) {
+		mailboxes, err = client.ListMailboxes(fqdn)
+		return
+	})
+	if err != nil {
+		if !notFound(err) {
+			g.warn("failed to list the mailboxes of %s: %s", fqdn, err)
+		}
+		return
+	}
+	for _, m := range mailboxes {
+		var mailbox email.MailboxResponse
+		err := g.clients.call(g.ctx, func() (err error) {
+			mailbox, err = client.GetMailbox(fqdn, m.ID)
+			return
+		})
+		if err != nil {
+			g.warn("failed to get the mailbox %s of %s: %s", m.Login, fqdn, err)
+			continue
+		}
+		block := &hclBlock{}
+		block.attribute("domain", fqdn)
+		block.attribute("login", mailbox.Login)
+		block.attribute("password", "")
+		block.attribute("mailbox_type", mailbox.MailboxType)
+		if len(mailbox.Aliases) > 0 {
+			block.attribute("aliases", mailbox.Aliases)
+		}
+		block.block("lifecycle").attribute("ignore_changes", hclExpression("[password]"))
+		g.resource("gandi_mailbox", fqdn+"/"+mailbox.ID, block, fqdn, mailbox.Login)
+	}
+}
+
+func (g *importConfigGenerator) forwards(fqdn string) {
+	var forwards []email.GetForwardRequest
+	err := g.clients.call(g.ctx, func() (err error) {
+		forwards, err = g.clients.Email.GetForwards(fqdn)
+		return
+	})
+	if err != nil {
+		if !notFound(err) {
+			g.warn("failed to list the email forwards of %s: %s", fqdn, err)
+		}
+		return
+	}
+	for _, f := range forwards {
+		source := f.Source + "@" + fqdn
+		block := &hclBlock{}
+		block.attribute("source", source)
+		block.attribute("destinations", f.Destinations)
+		g.resource("gandi_email_forwarding", source, block, fqdn, f.Source)
+	}
+}
+
+func (g *importConfigGenerator) simpleHostingInstances() {
+	var instances []simplehosting.Instance
+	err := g.clients.call(g.ctx, func() (err error) {
+		instances, err = g.clients.SimpleHosting.ListInstances()
+		return
+	})
+	if err != nil {
+		if !notFound(err) {
+			g.warn("failed to list the SimpleHosting instances: %s", err)
+		}
+		return
+	}
+	sort.Slice(instances, func(i, j int) bool { return instances[i].Name < instances[j].Name })
+	for _, i := range instances {
+		block := &hclBlock{}
+		block.attribute("name", i.Name)
+		block.attribute("size", i.Size)
+		if i.Datacenter != nil {
+			block.attribute("location", i.Datacenter.Region)
+		}
+		if i.Database != nil {
+			block.attribute("database_name", i.Database.Name)
+		}
+		if i.Language != nil {
+			block.attribute("language_name", i.Language.Name)
+		}
+		g.resource("gandi_simplehosting_instance", i.ID, block, i.Name)
+	}
+}
+
+// hclBlock is a block of the generated configuration. Its attributes
+// are written before its nested blocks, aligned as terraform fmt does.
+type hclBlock struct {
+	header     string
+	attributes []hclAttribute
+	blocks     []*hclBlock
+}
+
+type hclAttribute struct {
+	name  string
+	value interface{}
+}
+
+// hclExpression is written as is, such as a reference to a resource
+type hclExpression string
+
+func (b *hclBlock) attribute(name string, value interface{}) {
+	b.attributes = append(b.attributes, hclAttribute{name: name, value: value})
+}
+
+func (b *hclBlock) block(header string) *hclBlock {
+	block := &hclBlock{header: header}
+	b.blocks = append(b.blocks, block)
+	return block
+}
+
+// render returns the block as HCL
+func (b *hclBlock) render() (string, error) {
+	var s strings.Builder
+	if err := b.write(&s, ""); err != nil {
+		return "", err
+	}
+	return s.String(), nil
+}
+
+func (b *hclBlock) write(s *strings.Builder, indent string) error {
+	s.WriteString(indent + b.header + " {\n")
+	width := 0
+	for _, a := range b.attributes {
+		if len(a.name) > width {
+			width = len(a.name)
+		}
+	}
+	for _, a := range b.attributes {
+		value, err := hclValue(a.value)
+		if err != nil {
+			return fmt.Errorf("invalid attribute %s: %w", a.name, err)
+		}
+		fmt.Fprintf(s, "%s  %-*s = %s\n", indent, width, a.name, value)
+	}
+	for i, block := range b.blocks {
+		if i > 0 || len(b.attributes) > 0 {
+			s.WriteString("\n")
+		}
+		if err := block.write(s, indent+"  "); err != nil {
+			return err
+		}
+	}
+	s.WriteString(indent + "}\n")
+	return nil
+}
+
+func hclValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case hclExpression:
+		return string(v), nil
+	case string:
+		return hclString(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case []string:
+		values := make([]string, 0, len(v))
+		for _, s := range v {
+			values = append(values, hclString(s))
+		}
+		return "[" + strings.Join(values, ", ") + "]", nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		elements := make([]string, 0, len(keys))
+		for _, key := range keys {
+			elements = append(elements, hclString(key)+" = "+hclString(fmt.Sprint(v[key])))
+		}
+		return "{ " + strings.Join(elements, ", ") + " }", nil
+	}
+	return "", fmt.Errorf("unsupported HCL value %#v", value)
+}
+
+// hclString quotes s as an HCL string literal, escaping the template
+// sequences
+func hclString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < 0x20:
+			fmt.Fprintf(&b, `\u%04x`, r)
+		case (r == '$' || r == '%') && strings.HasPrefix(s[i+1:], "{"):
+			b.WriteRune(r)
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
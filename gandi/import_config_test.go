@@ -0,0 +1,333 @@
+package gandi
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-gandi/go-gandi/domain"
+	"github.com/go-gandi/go-gandi/livedns"
+)
+
+func TestOfflineImportConfig(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	p := newTestOfflineProvider(t, api)
+	api.addDomain("example.com")
+	api.addZone("example.com",
+		livedns.DomainRecord{RrsetName: "@", RrsetType: "NS", RrsetValues: fakeLiveDNSNameservers},
+		livedns.DomainRecord{RrsetName: "www", RrsetType: "A", RrsetTTL: 300, RrsetValues: []string{"192.0.2.1"}},
+		livedns.DomainRecord{RrsetName: "@", RrsetType: "TXT", RrsetValues: []string{"v=spf1 -all"}},
+	)
+	api.addDomain("example.org")
+	api.domains["example.org"].liveDNS = false
+	api.domains["example.org"].details.Nameservers = []string{"ns1.example.net"}
+	api.domains["example.org"].details.Tags = []string{"prod"}
+	owner := &domain.Contact{
+		Country: "FR", Email: "jane@example.org", FamilyName: "Doe", GivenName: "Jane",
+		StreetAddr: "1 rue de la Paix", City: "Paris", Zip: "75002", Phone: "+33.123456789",
+		ContactType: 1, OrgName: "Example", DataObfuscated: Bool(false), MailObfuscated: Bool(true),
+	}
+	api.domains["example.org"].details.Contacts = &domain.Contacts{Owner: owner, Admin: owner, Billing: owner, Tech: owner}
+
+	p.apply("gandi_glue_record", nil, map[string]interface{}{
+		"zone": "example.com",
+		"name": "ns1",
+		"ips":  []interface{}{"192.0.2.53"},
+	})
+	key := p.apply("gandi_dnssec_key", nil, map[string]interface{}{
+		"domain":     "example.com",
+		"algorithm":  13,
+		"type":       "ksk",
+		"public_key": "AwEAAc==",
+	})
+	mailbox := p.apply("gandi_mailbox", nil, map[string]interface{}{
+		"domain":   "example.com",
+		"login":    "john",
+		"password": "secret",
+		"aliases":  []interface{}{"jdoe"},
+	})
+	p.apply("gandi_email_forwarding", nil, map[string]interface{}{
+		"source":       "contact@example.com",
+		"destinations": []interface{}{"john@example.com"},
+	})
+	instance := p.apply("gandi_simplehosting_instance", nil, map[string]interface{}{
+		"name":          "my-site",
+		"size":          "s+",
+		"location":      "FR",
+		"database_name": "mysql",
+		"language_name": "php",
+	})
+
+	var config strings.Builder
+	if err := generateImportConfig(context.Background(), p.provider.Meta().(*clients), &config, ImportConfigOptions{}); err != nil {
+		t.Fatalf("failed to generate the import config: %s", err)
+	}
+	expected := `# Generated by terraform-provider-gandi generate-imports.
+# The passwords of the mailboxes can't be read from the Gandi API: they
+# are ignored until they are set.
+
+import {
+  to = gandi_domain.example_com
+  id = "example.com"
+}
+
+resource "gandi_domain" "example_com" {
+  name          = "example.com"
+  autorenew     = false
+  transfer_lock = false
+
+  owner {
+    country         = "FR"
+    data_obfuscated = true
+    email           = "john@example.com"
+    family_name     = "Doe"
+    given_name      = "John"
+    mail_obfuscated = true
+    type            = "person"
+  }
+}
+
+import {
+  to = gandi_livedns_record.example_com_apex_txt
+  id = "example.com/@/TXT"
+}
+
+resource "gandi_livedns_record" "example_com_apex_txt" {
+  zone   = "example.com"
+  name   = "@"
+  type   = "TXT"
+  ttl    = 10800
+  values = ["\"v=spf1 -all\""]
+}
+
+import {
+  to = gandi_livedns_record.example_com_www_a
+  id = "example.com/www/A"
+}
+
+resource "gandi_livedns_record" "example_com_www_a" {
+  zone   = "example.com"
+  name   = "www"
+  type   = "A"
+  ttl    = 300
+  values = ["192.0.2.1"]
+}
+
+import {
+  to = gandi_glue_record.example_com_ns1
+  id = "example.com/ns1"
+}
+
+resource "gandi_glue_record" "example_com_ns1" {
+  zone = "example.com"
+  name = "ns1"
+  ips  = ["192.0.2.53"]
+}
+
+import {
+  to = gandi_dnssec_key.example_com_` + key.ID + `
+  id = "example.com/` + key.ID + `"
+}
+
+resource "gandi_dnssec_key" "example_com_` + key.ID + `" {
+  domain     = "example.com"
+  algorithm  = 13
+  type       = "ksk"
+  public_key = "AwEAAc=="
+}
+
+import {
+  to = gandi_mailbox.example_com_john
+  id = "example.com/` + mailbox.ID + `"
+}
+
+resource "gandi_mailbox" "example_com_john" {
+  domain       = "example.com"
+  login        = "john"
+  password     = ""
+  mailbox_type = "standard"
+  aliases      = ["jdoe"]
+
+  lifecycle {
+    ignore_changes = [password]
+  }
+}
+
+import {
+  to = gandi_email_forwarding.example_com_contact
+  id = "contact@example.com"
+}
+
+resource "gandi_email_forwarding" "example_com_contact" {
+  source       = "contact@example.com"
+  destinations = ["john@example.com"]
+}
+
+import {
+  to = gandi_domain.example_org
+  id = "example.org"
+}
+
+resource "gandi_domain" "example_org" {
+  name          = "example.org"
+  autorenew     = false
+  tags          = ["prod"]
+  transfer_lock = false
+
+  owner {
+    city            = "Paris"
+    country         = "FR"
+    email           = "jane@example.org"
+    family_name     = "Doe"
+    given_name      = "Jane"
+    mail_obfuscated = true
+    organisation    = "Example"
+    phone           = "+33.123456789"
+    street_addr     = "1 rue de la Paix"
+    type            = "company"
+    zip             = "75002"
+  }
+
+  lifecycle {
+    ignore_changes = [nameservers]
+  }
+}
+
+import {
+  to = gandi_nameservers.example_org
+  id = "example.org"
+}
+
+resource "gandi_nameservers" "example_org" {
+  domain      = "example.org"
+  nameservers = ["ns1.example.net"]
+}
+
+import {
+  to = gandi_simplehosting_instance.my_site
+  id = "` + instance.ID + `"
+}
+
+resource "gandi_simplehosting_instance" "my_site" {
+  name          = "my-site"
+  size          = "s+"
+  location      = "FR"
+  database_name = "mysql"
+  language_name = "php"
+}
+`
+	if config.String() != expected {
+		t.Fatalf("unexpected import config:\n%s\nexpected:\n%s", config.String(), expected)
+	}
+
+	// The generated resources are imported without any diff. The
+	// nameservers of the domain are ignored by its lifecycle block, as
+	// if they were configured as they are.
+	imported := p.importState("gandi_domain", "example.org")
+	p.planEmpty("gandi_domain", imported, map[string]interface{}{
+		"name":          "example.org",
+		"autorenew":     false,
+		"nameservers":   []interface{}{"ns1.example.net"},
+		"tags":          []interface{}{"prod"},
+		"transfer_lock": false,
+		"owner": []interface{}{map[string]interface{}{
+			"city":            "Paris",
+			"country":         "FR",
+			"email":           "jane@example.org",
+			"family_name":     "Doe",
+			"given_name":      "Jane",
+			"mail_obfuscated": true,
+			"organisation":    "Example",
+			"phone":           "+33.123456789",
+			"street_addr":     "1 rue de la Paix",
+			"type":            "company",
+			"zip":             "75002",
+		}},
+	})
+	imported = p.importState("gandi_nameservers", "example.org")
+	p.planEmpty("gandi_nameservers", imported, map[string]interface{}{
+		"domain":      "example.org",
+		"nameservers": []interface{}{"ns1.example.net"},
+	})
+	imported = p.importState("gandi_livedns_record", "example.com/@/TXT")
+	p.planEmpty("gandi_livedns_record", imported, map[string]interface{}{
+		"zone":   "example.com",
+		"name":   "@",
+		"type":   "TXT",
+		"ttl":    10800,
+		"values": []interface{}{`"v=spf1 -all"`},
+	})
+	// The password is ignored by the lifecycle block, as if it was
+	// configured as it is in the state
+	imported = p.importState("gandi_mailbox", "example.com/"+mailbox.ID)
+	p.planEmpty("gandi_mailbox", imported, map[string]interface{}{
+		"domain":       "example.com",
+		"login":        "john",
+		"password":     imported.Attributes["password"],
+		"mailbox_type": "standard",
+		"aliases":      []interface{}{"jdoe"},
+	})
+	imported = p.importState("gandi_simplehosting_instance", instance.ID)
+	p.planEmpty("gandi_simplehosting_instance", imported, map[string]interface{}{
+		"name":          "my-site",
+		"size":          "s+",
+		"location":      "FR",
+		"database_name": "mysql",
+		"language_name": "php",
+	})
+}
+
+func TestOfflineImportConfig_domains(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	p := newTestOfflineProvider(t, api)
+	api.addDomain("example.com")
+	api.addDomain("example.org")
+	api.mu.Lock()
+	api.domains["example.com"].dnssecKeys = []domain.DNSSECKey{{ID: 1, Algorithm: 13, Type: "ksk", PublicKey: "AwEAAc=="}}
+	api.mu.Unlock()
+
+	var config strings.Builder
+	if err := generateImportConfig(context.Background(), p.provider.Meta().(*clients), &config, ImportConfigOptions{Domains: []string{"example.org"}}); err != nil {
+		t.Fatalf("failed to generate the import config: %s", err)
+	}
+	if !strings.Contains(config.String(), `resource "gandi_domain" "example_org"`) {
+		t.Fatalf("the selected domain should have been generated:\n%s", config.String())
+	}
+	if strings.Contains(config.String(), `id = "example.com`) {
+		t.Fatalf("only the selected domains should have been generated:\n%s", config.String())
+	}
+
+	err := generateImportConfig(context.Background(), p.provider.Meta().(*clients), &config, ImportConfigOptions{Domains: []string{"unknown.com"}})
+	if err == nil || !strings.Contains(err.Error(), "unknown.com") {
+		t.Fatalf("an unknown domain should fail, got %v", err)
+	}
+}
+
+func TestHCLString(t *testing.T) {
+	for value, expected := range map[string]string{
+		`example.com`:         `"example.com"`,
+		`"v=spf1 -all"`:       `"\"v=spf1 -all\""`,
+		`C:\path`:             `"C:\\path"`,
+		"line\nbreak":         `"line\nbreak"`,
+		"${var} %{if} $ % {}": `"$${var} %%{if} $ % {}"`,
+	} {
+		if got := hclString(value); got != expected {
+			t.Errorf("hclString(%q) = %s, expected %s", value, got, expected)
+		}
+	}
+}
+
+func TestHCLBlock_unsupportedValue(t *testing.T) {
+	block := &hclBlock{header: `resource "gandi_domain" "example_com"`}
+	block.block("owner").attribute("price", 1.5)
+
+	var config strings.Builder
+	g := &importConfigGenerator{w: &config, names: make(map[string]bool)}
+	g.writeBlock(block)
+	if g.err == nil || !strings.Contains(g.err.Error(), "price") {
+		t.Fatalf("an unsupported value should fail the generation, got %v", g.err)
+	}
+	if config.Len() != 0 {
+		t.Fatalf("nothing should have been written, got:\n%s", config.String())
+	}
+}
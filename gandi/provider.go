@@ -1,6 +1,10 @@
 package gandi
 
 import (
+	"context"
+	"fmt"
+	"os"
+
 	"github.com/go-gandi/go-gandi"
 	"github.com/go-gandi/go-gandi/certificate"
 	"github.com/go-gandi/go-gandi/config"
@@ -8,6 +12,8 @@ import (
 	"github.com/go-gandi/go-gandi/email"
 	"github.com/go-gandi/go-gandi/livedns"
 	"github.com/go-gandi/go-gandi/simplehosting"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -49,27 +55,54 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("GANDI_URL", "https://api.gandi.net"),
 				Description: "The Gandi API URL",
 			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      3,
+				Description:  "The number of retries of the requests throttled by the Gandi API or failing with a 502, 503 or 504 status code",
+				ValidateFunc: validateNonNegativeInt,
+			},
+			"rate_limit": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Default:      0,
+				Description:  "The maximum number of requests per second sent to the Gandi API, unlimited when 0",
+				ValidateFunc: validateNonNegativeFloat,
+			},
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"gandi_livedns_domain":    dataSourceLiveDNSDomain(),
-			"gandi_livedns_domain_ns": dataSourceLiveDNSDomainNS(),
-			"gandi_domain":            dataSourceDomain(),
-			"gandi_mailbox":           dataSourceMailbox(),
-			"gandi_glue_record":       dataSourceGlueRecord(),
+			"gandi_livedns_domain":      dataSourceLiveDNSDomain(),
+			"gandi_livedns_domain_ns":   dataSourceLiveDNSDomainNS(),
+			"gandi_livedns_zonefile":    dataSourceLiveDNSZoneFile(),
+			"gandi_livedns_records":     dataSourceLiveDNSRecords(),
+			"gandi_livedns_snapshots":   dataSourceLiveDNSSnapshots(),
+			"gandi_domain":              dataSourceDomain(),
+			"gandi_domain_availability": dataSourceDomainAvailability(),
+			"gandi_domain_authinfo":     dataSourceDomainAuthInfo(),
+			"gandi_domains":             dataSourceDomains(),
+			"gandi_mailbox":             dataSourceMailbox(),
+			"gandi_glue_record":         dataSourceGlueRecord(),
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"gandi_livedns_domain":         resourceLiveDNSDomain(),
-			"gandi_livedns_record":         resourceLiveDNSRecord(),
-			"gandi_domain":                 resourceDomain(),
-			"gandi_mailbox":                resourceMailbox(),
-			"gandi_email_forwarding":       resourceEmailForwarding(),
-			"gandi_dnssec_key":             resourceDNSSECKey(),
-			"gandi_simplehosting_instance": resourceSimpleHostingInstance(),
-			"gandi_glue_record":            resourceGlueRecord(),
-			"gandi_simplehosting_vhost":    resourceSimpleHostingVhost(),
-			"gandi_nameservers":            resourceNameservers(),
+			"gandi_livedns_domain":           resourceLiveDNSDomain(),
+			"gandi_livedns_record":           resourceLiveDNSRecord(),
+			"gandi_livedns_zone_records":     resourceLiveDNSZoneRecords(),
+			"gandi_livedns_zonefile":         resourceLiveDNSZoneFile(),
+			"gandi_livedns_snapshot":         resourceLiveDNSSnapshot(),
+			"gandi_livedns_snapshot_restore": resourceLiveDNSSnapshotRestore(),
+			"gandi_domain":                   resourceDomain(),
+			"gandi_domain_transfer":          resourceDomainTransfer(),
+			"gandi_domain_renewal":           resourceDomainRenewal(),
+			"gandi_domain_authinfo":          resourceDomainAuthInfo(),
+			"gandi_mailbox":                  resourceMailbox(),
+			"gandi_email_forwarding":         resourceEmailForwarding(),
+			"gandi_dnssec_key":               resourceDNSSECKey(),
+			"gandi_simplehosting_instance":   resourceSimpleHostingInstance(),
+			"gandi_glue_record":              resourceGlueRecord(),
+			"gandi_simplehosting_vhost":      resourceSimpleHostingVhost(),
+			"gandi_nameservers":              resourceNameservers(),
 		},
-		ConfigureFunc: getGandiClients,
+		ConfigureContextFunc: getGandiClients,
 	}
 }
 
@@ -79,9 +112,26 @@ type clients struct {
 	LiveDNS       *livedns.LiveDNS
 	SimpleHosting *simplehosting.SimpleHosting
 	Certificate   *certificate.Certificate
+	// API is used for the endpoints not covered by go-gandi
+	API *apiClient
+	// transport applies the settings of the provider to the requests
+	transport *providerTransport
+	// Cache shares the reads of the zones and domains during a run
+	Cache *readCache
 }
 
-func getGandiClients(d *schema.ResourceData) (interface{}, error) {
+func getGandiClients(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	// The transport aborts the requests when Terraform stops the
+	// provider, rate limits and retries them. go-gandi doesn't take a
+	// context nor a transport: its requests are sent with call, which
+	// can only stop waiting for them.
+	stop, _ := schema.StopContext(ctx)
+	transport := newProviderTransport(transportSettings{
+		stop:       stop,
+		limiter:    newRateLimiter(d.Get("rate_limit").(float64)),
+		maxRetries: d.Get("max_retries").(int),
+	})
+
 	config := config.Config{
 		APIURL:              d.Get("url").(string),
 		APIKey:              d.Get("key").(string),
@@ -89,12 +139,14 @@ func getGandiClients(d *schema.ResourceData) (interface{}, error) {
 		SharingID:           d.Get("sharing_id").(string),
 		DryRun:              d.Get("dry_run").(bool),
 		Debug:               logging.IsDebugOrHigher(),
+		Timeout:             requestAttemptTimeout,
 	}
 	liveDNS := gandi.NewLiveDNSClient(config)
 	email := gandi.NewEmailClient(config)
 	domainClient := gandi.NewDomainClient(config)
 	simpleHostingClient := gandi.NewSimpleHostingClient(config)
 	certificateClient := gandi.NewCertificateClient(config)
+	apiClient := newAPIClient(config, transport)
 
 	return &clients{
 		Domain:        domainClient,
@@ -102,5 +154,57 @@ func getGandiClients(d *schema.ResourceData) (interface{}, error) {
 		LiveDNS:       liveDNS,
 		SimpleHosting: simpleHostingClient,
 		Certificate:   certificateClient,
-	}, nil
+		API:           apiClient,
+		transport:     transport,
+		Cache:         newReadCache(liveDNS, domainClient, apiClient, transport),
+	}, deprecatedEnvironmentDiags(d)
+}
+
+// call sends a request of go-gandi, which must be idempotent, bound to
+// ctx and with the settings of the provider.
+func (c *clients) call(ctx context.Context, f func() error) error {
+	return c.transport.call(ctx, true, f)
+}
+
+// callNonIdempotent sends a request of go-gandi which isn't idempotent,
+// such as a POST, only retried when it is throttled.
+func (c *clients) callNonIdempotent(ctx context.Context, f func() error) error {
+	return c.transport.call(ctx, false, f)
+}
+
+// deprecatedEnvironmentDiags warns about the deprecated attributes set
+// by environment variables: Terraform only reports the ones set in the
+// configuration.
+func deprecatedEnvironmentDiags(d *schema.ResourceData) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, deprecated := range []struct{ attribute, variable string }{
+		{"key", "GANDI_KEY"},
+		{"sharing_id", "GANDI_SHARING_ID"},
+	} {
+		value := os.Getenv(deprecated.variable)
+		if value == "" || d.Get(deprecated.attribute).(string) != value {
+			continue
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Warning,
+			Summary:       fmt.Sprintf("The %s environment variable is deprecated", deprecated.variable),
+			Detail:        fmt.Sprintf("The %s attribute it sets is deprecated: use personal_access_token or the GANDI_PERSONAL_ACCESS_TOKEN environment variable instead.", deprecated.attribute),
+			AttributePath: cty.GetAttrPath(deprecated.attribute),
+		})
+	}
+	return diags
+}
+
+func validateNonNegativeInt(val interface{}, key string) (warns []string, errs []error) {
+	if v := val.(int); v < 0 {
+		errs = append(errs, fmt.Errorf("%q must be a positive number or 0. Got %d", key, v))
+	}
+	return
+}
+
+func validateNonNegativeFloat(val interface{}, key string) (warns []string, errs []error) {
+	if v := val.(float64); v < 0 {
+		errs = append(errs, fmt.Errorf("%q must be a positive number or 0. Got %v", key, v))
+	}
+	return
 }
@@ -1,10 +1,15 @@
 package gandi
 
 import (
+	"context"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 var testAccProviders map[string]*schema.Provider
@@ -35,3 +40,231 @@ func testAccPreCheck(t *testing.T) {
 		t.Fatal("GANDI_URL must be set for acceptance tests")
 	}
 }
+
+// testOfflineProvider drives the provider resources against a
+// fakeGandiAPI, the same way Terraform does on plan, apply, refresh
+// and import, but without requiring a Terraform binary.
+type testOfflineProvider struct {
+	t        *testing.T
+	provider *schema.Provider
+}
+
+func newTestOfflineProvider(t *testing.T, api *fakeGandiAPI) *testOfflineProvider {
+	return newTestOfflineProviderWithConfig(t, api, map[string]interface{}{})
+}
+
+func newTestOfflineProviderWithConfig(t *testing.T, api *fakeGandiAPI, config map[string]interface{}) *testOfflineProvider {
+	t.Helper()
+	config["url"] = api.server.URL
+	config["personal_access_token"] = "fake-personal-access-token"
+	provider := Provider()
+	if diags := provider.Configure(context.Background(), terraform.NewResourceConfigRaw(config)); diags.HasError() {
+		t.Fatalf("failed to configure the provider: %v", diags)
+	}
+	return &testOfflineProvider{t: t, provider: provider}
+}
+
+func (p *testOfflineProvider) resource(resourceType string) *schema.Resource {
+	p.t.Helper()
+	r, ok := p.provider.ResourcesMap[resourceType]
+	if !ok {
+		p.t.Fatalf("unknown resource type %s", resourceType)
+	}
+	return r
+}
+
+// plan validates the config and returns the diff against the state.
+func (p *testOfflineProvider) plan(resourceType string, state *terraform.InstanceState, config map[string]interface{}) (*terraform.InstanceDiff, diag.Diagnostics) {
+	p.t.Helper()
+	r := p.resource(resourceType)
+	c := terraform.NewResourceConfigRaw(config)
+	if diags := p.provider.ValidateResource(resourceType, c); diags.HasError() {
+		return nil, diags
+	}
+	diff, err := r.Diff(context.Background(), state, c, p.provider.Meta())
+	return diff, diag.FromErr(err)
+}
+
+// applyWithDiags plans and applies the config, and returns the new
+// state along with the diagnostics.
+func (p *testOfflineProvider) applyWithDiags(resourceType string, state *terraform.InstanceState, config map[string]interface{}) (*terraform.InstanceState, diag.Diagnostics) {
+	p.t.Helper()
+	diff, diags := p.plan(resourceType, state, config)
+	if diags.HasError() {
+		return state, diags
+	}
+	if diff == nil || diff.Empty() {
+		return state, diags
+	}
+	newState, applyDiags := p.resource(resourceType).Apply(context.Background(), state, diff, p.provider.Meta())
+	return newState, append(diags, applyDiags...)
+}
+
+func (p *testOfflineProvider) apply(resourceType string, state *terraform.InstanceState, config map[string]interface{}) *terraform.InstanceState {
+	p.t.Helper()
+	newState, diags := p.applyWithDiags(resourceType, state, config)
+	if diags.HasError() {
+		p.t.Fatalf("failed to apply %s: %v", resourceType, diags)
+	}
+	return newState
+}
+
+// planEmpty fails the test if the config differs from the state.
+func (p *testOfflineProvider) planEmpty(resourceType string, state *terraform.InstanceState, config map[string]interface{}) {
+	p.t.Helper()
+	diff, diags := p.plan(resourceType, state, config)
+	if diags.HasError() {
+		p.t.Fatalf("failed to plan %s: %v", resourceType, diags)
+	}
+	if diff != nil && !diff.Empty() {
+		p.t.Fatalf("expected an empty plan for %s, got %s", resourceType, diff.GoString())
+	}
+}
+
+func (p *testOfflineProvider) refreshWithDiags(resourceType string, state *terraform.InstanceState) (*terraform.InstanceState, diag.Diagnostics) {
+	p.t.Helper()
+	return p.resource(resourceType).RefreshWithoutUpgrade(context.Background(), state, p.provider.Meta())
+}
+
+func (p *testOfflineProvider) refresh(resourceType string, state *terraform.InstanceState) *terraform.InstanceState {
+	p.t.Helper()
+	newState, diags := p.refreshWithDiags(resourceType, state)
+	if diags.HasError() {
+		p.t.Fatalf("failed to refresh %s: %v", resourceType, diags)
+	}
+	return newState
+}
+
+func (p *testOfflineProvider) destroy(resourceType string, state *terraform.InstanceState) {
+	p.t.Helper()
+	if diags := p.destroyWithDiags(resourceType, state); diags.HasError() {
+		p.t.Fatalf("failed to destroy %s: %v", resourceType, diags)
+	}
+}
+
+// destroyWithDiags destroys the resource and returns the diagnostics
+func (p *testOfflineProvider) destroyWithDiags(resourceType string, state *terraform.InstanceState) diag.Diagnostics {
+	p.t.Helper()
+	_, diags := p.resource(resourceType).Apply(context.Background(), state, &terraform.InstanceDiff{Destroy: true}, p.provider.Meta())
+	return diags
+}
+
+// importState imports the resource identified by id and refreshes it.
+func (p *testOfflineProvider) importState(resourceType string, id string) *terraform.InstanceState {
+	p.t.Helper()
+	r := p.resource(resourceType)
+	d := r.Data(&terraform.InstanceState{ID: id})
+	var imported []*schema.ResourceData
+	var err error
+	if r.Importer.StateContext != nil {
+		imported, err = r.Importer.StateContext(context.Background(), d, p.provider.Meta())
+	} else {
+		imported, err = r.Importer.State(d, p.provider.Meta())
+	}
+	if err != nil {
+		p.t.Fatalf("failed to import %s %s: %s", resourceType, id, err)
+	}
+	if len(imported) != 1 {
+		p.t.Fatalf("expected a single resource to be imported, got %d", len(imported))
+	}
+	state := p.refresh(resourceType, imported[0].State())
+	if state == nil {
+		p.t.Fatalf("the imported resource %s %s does not exist", resourceType, id)
+	}
+	return state
+}
+
+func (p *testOfflineProvider) readDataSourceWithDiags(dataSourceType string, config map[string]interface{}) (*terraform.InstanceState, diag.Diagnostics) {
+	p.t.Helper()
+	r, ok := p.provider.DataSourcesMap[dataSourceType]
+	if !ok {
+		p.t.Fatalf("unknown data source type %s", dataSourceType)
+	}
+	c := terraform.NewResourceConfigRaw(config)
+	if diags := p.provider.ValidateDataSource(dataSourceType, c); diags.HasError() {
+		return nil, diags
+	}
+	diff, err := r.Diff(context.Background(), nil, c, p.provider.Meta())
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	return r.ReadDataApply(context.Background(), diff, p.provider.Meta())
+}
+
+func (p *testOfflineProvider) readDataSource(dataSourceType string, config map[string]interface{}) *terraform.InstanceState {
+	p.t.Helper()
+	state, diags := p.readDataSourceWithDiags(dataSourceType, config)
+	if diags.HasError() {
+		p.t.Fatalf("failed to read %s: %v", dataSourceType, diags)
+	}
+	return state
+}
+
+// testCheckAttributes fails the test if the state attributes don't
+// contain the expected values.
+func testCheckAttributes(t *testing.T, state *terraform.InstanceState, expected map[string]string) {
+	t.Helper()
+	if state == nil {
+		t.Fatalf("the resource does not exist")
+	}
+	for k, v := range expected {
+		if got, ok := state.Attributes[k]; !ok || got != v {
+			t.Errorf("expected attribute %s to be %q, got %q", k, v, got)
+		}
+	}
+}
+
+func TestOfflineProvider_deprecatedEnvironment(t *testing.T) {
+	for variable, value := range map[string]string{"GANDI_KEY": "fake-api-key", "GANDI_SHARING_ID": ""} {
+		variable := variable
+		previous, set := os.LookupEnv(variable)
+		os.Setenv(variable, value)
+		t.Cleanup(func() {
+			if set {
+				os.Setenv(variable, previous)
+			} else {
+				os.Unsetenv(variable)
+			}
+		})
+	}
+
+	provider := Provider()
+	diags := provider.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{}))
+	if len(diags) != 1 || diags[0].Severity != diag.Warning || !diags[0].AttributePath.Equals(cty.GetAttrPath("key")) {
+		t.Fatalf("expected a warning about the GANDI_KEY environment variable, got %v", diags)
+	}
+}
+
+func TestOfflineProvider_retryThrottled(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addZone("example.com")
+	// go-gandi doesn't return the Retry-After header of the throttled
+	// responses
+	withRetryBaseDelay(t, time.Millisecond)
+
+	p := newTestOfflineProvider(t, api)
+	api.throttled = 2
+	p.apply("gandi_livedns_record", nil, map[string]interface{}{
+		"zone":   "example.com",
+		"name":   "www",
+		"type":   "A",
+		"ttl":    300,
+		"values": []interface{}{"192.0.2.1"},
+	})
+	if api.throttled != 0 {
+		t.Fatalf("expected the throttled requests to be retried")
+	}
+
+	p = newTestOfflineProviderWithConfig(t, api, map[string]interface{}{"max_retries": 0})
+	api.throttled = 1
+	_, diags := p.applyWithDiags("gandi_livedns_record", nil, map[string]interface{}{
+		"zone":   "example.com",
+		"name":   "api",
+		"type":   "A",
+		"ttl":    300,
+		"values": []interface{}{"192.0.2.2"},
+	})
+	if !diags.HasError() {
+		t.Fatalf("expected the throttled request to fail without retries")
+	}
+}
@@ -41,8 +41,8 @@ func resourceDNSSECKey() *schema.Resource {
 			},
 		},
 		CreateContext: resourceDNSSECKeyCreate,
-		Delete:        resourceDNSSECKeyDelete,
-		Read:          resourceDNSSECKeyRead,
+		DeleteContext: resourceDNSSECKeyDelete,
+		ReadContext:   resourceDNSSECKeyRead,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -50,7 +50,7 @@ func resourceDNSSECKey() *schema.Resource {
 }
 
 func resourceDNSSECKeyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*clients).Domain
+	c := meta.(*clients)
 	resDomain := d.Get("domain").(string)
 	publicKey := d.Get("public_key").(string)
 
@@ -60,13 +60,19 @@ func resourceDNSSECKeyCreate(ctx context.Context, d *schema.ResourceData, meta i
 		PublicKey: publicKey,
 	}
 
-	err := client.CreateDNSSECKey(resDomain, request)
+	err := c.callNonIdempotent(ctx, func() error {
+		return c.Domain.CreateDNSSECKey(resDomain, request)
+	})
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromRequestError(err, resourceDNSSECKey().Schema, nil)
 	}
 
 	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-		keys, err := client.ListDNSSECKeys(resDomain)
+		var keys []domain.DNSSECKey
+		err := c.call(ctx, func() (err error) {
+			keys, err = c.Domain.ListDNSSECKeys(resDomain)
+			return
+		})
 		if err != nil {
 			return resource.NonRetryableError(fmt.Errorf("error getting DNSSEC keys: %s", err))
 		}
@@ -82,11 +88,11 @@ func resourceDNSSECKeyCreate(ctx context.Context, d *schema.ResourceData, meta i
 		return diag.FromErr(err)
 	}
 
-	return diag.FromErr(resourceDNSSECKeyRead(d, meta))
+	return resourceDNSSECKeyRead(ctx, d, meta)
 }
 
-func resourceDNSSECKeyRead(d *schema.ResourceData, meta interface{}) (err error) {
-	client := meta.(*clients).Domain
+func resourceDNSSECKeyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
 	resDomain := d.Get("domain").(string)
 	id := d.Id()
 	if strings.Contains(id, "/") {
@@ -96,9 +102,13 @@ func resourceDNSSECKeyRead(d *schema.ResourceData, meta interface{}) (err error)
 		d.SetId(id)
 	}
 
-	keys, err := client.ListDNSSECKeys(resDomain)
-	if err != nil {
+	var keys []domain.DNSSECKey
+	err := c.call(ctx, func() (err error) {
+		keys, err = c.Domain.ListDNSSECKeys(resDomain)
 		return
+	})
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	var found domain.DNSSECKey
@@ -111,27 +121,26 @@ func resourceDNSSECKeyRead(d *schema.ResourceData, meta interface{}) (err error)
 		}
 	}
 	if !matchedKey {
-		err = fmt.Errorf("Cannot find DNSSEC key %s for domain %s", id, resDomain)
-		return
+		return diag.Errorf("Cannot find DNSSEC key %s for domain %s", id, resDomain)
 	}
 
 	if err = d.Set("algorithm", found.Algorithm); err != nil {
-		return fmt.Errorf("failed to set algorithm for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set algorithm for %s: %w", d.Id(), err))
 	}
 	if err = d.Set("type", found.Type); err != nil {
-		return fmt.Errorf("failed to set type for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set type for %s: %w", d.Id(), err))
 	}
 	if err = d.Set("public_key", found.PublicKey); err != nil {
-		return fmt.Errorf("failed to set public key for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set public key for %s: %w", d.Id(), err))
 	}
 	if err = d.Set("domain", resDomain); err != nil {
-		return fmt.Errorf("failed to set domain for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set domain for %s: %w", d.Id(), err))
 	}
-	return
+	return nil
 }
 
-func resourceDNSSECKeyDelete(d *schema.ResourceData, meta interface{}) (err error) {
-	client := meta.(*clients).Domain
+func resourceDNSSECKeyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
 	domain := d.Get("domain").(string)
 	id := d.Id()
 	if strings.Contains(id, "/") {
@@ -141,6 +150,7 @@ func resourceDNSSECKeyDelete(d *schema.ResourceData, meta interface{}) (err erro
 		d.SetId(id)
 	}
 
-	err = client.DeleteDNSSECKey(domain, id)
-	return
+	return diag.FromErr(c.call(ctx, func() error {
+		return c.Domain.DeleteDNSSECKey(domain, id)
+	}))
 }
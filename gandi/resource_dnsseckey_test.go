@@ -0,0 +1,40 @@
+package gandi
+
+import (
+	"testing"
+)
+
+func TestOfflineDNSSECKey_basic(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addDomain("example.com")
+	p := newTestOfflineProvider(t, api)
+	config := map[string]interface{}{
+		"domain":     "example.com",
+		"algorithm":  13,
+		"type":       "ksk",
+		"public_key": "mdsswUyr3DPW132mOi8V9xESWE8jTo0dxCjjnopKl+GqJxpVXckHAeF+KkxLbxILfDLUT0rAK9iUzy1L53eKGQ==",
+	}
+
+	state := p.apply("gandi_dnssec_key", nil, config)
+	if state.ID == "" {
+		t.Fatalf("the DNSSEC key ID should have been set")
+	}
+	testCheckAttributes(t, state, map[string]string{
+		"domain":    "example.com",
+		"algorithm": "13",
+		"type":      "ksk",
+	})
+	p.planEmpty("gandi_dnssec_key", state, config)
+
+	imported := p.importState("gandi_dnssec_key", "example.com/"+state.ID)
+	testCheckAttributes(t, imported, map[string]string{
+		"id":         state.ID,
+		"domain":     "example.com",
+		"public_key": config["public_key"].(string),
+	})
+
+	p.destroy("gandi_dnssec_key", state)
+	if keys := api.domains["example.com"].dnssecKeys; len(keys) != 0 {
+		t.Fatalf("the DNSSEC key should have been deleted, got %v", keys)
+	}
+}
@@ -3,23 +3,28 @@ package gandi
 import (
 	"context"
 	"fmt"
+	"log"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/go-gandi/go-gandi/domain"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceDomain() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		CreateContext: resourceDomainCreate,
-		Read:          resourceDomainRead,
-		Update:        resourceDomainUpdate,
-		Delete:        resourceDomainDelete,
+		ReadContext:   resourceDomainRead,
+		UpdateContext: resourceDomainUpdate,
+		DeleteContext: resourceDomainDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		CustomizeDiff: resourceDomainCustomizeDiff,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -48,9 +53,88 @@ func resourceDomain() *schema.Resource {
 				Optional:    true,
 				Description: "A list of tags attached to the domain",
 			},
+			"duration": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ValidateFunc:     validateDomainDuration,
+				DiffSuppressFunc: suppressRegisteredDomainDiff,
+				Description:      "The registration period of the domain in years, 1 by default. It is only used when the domain is registered.",
+			},
+			"max_price": {
+				Type:             schema.TypeFloat,
+				Optional:         true,
+				ValidateFunc:     validateNonNegativeFloat,
+				DiffSuppressFunc: suppressRegisteredDomainDiff,
+				Description:      "The maximum price of the registration for the whole duration, before taxes. The plan fails if the domain costs more. It is only used when the domain is registered.",
+			},
+			"currency": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validateCurrency,
+				DiffSuppressFunc: suppressRegisteredDomainDiff,
+				Description:      "The currency of the registration, such as 'EUR' or 'USD'. The currency of the account is used by default. It is only used when the domain is registered.",
+			},
+			"accept_premium": {
+				Type:             schema.TypeBool,
+				Optional:         true,
+				DiffSuppressFunc: suppressRegisteredDomainDiff,
+				Description:      "Whether a premium domain can be registered. The plan fails if the domain is premium and this is not set. It is only used when the domain is registered.",
+			},
+			"transfer_lock": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether the domain is locked against transfers to another registrar, with the clientTransferProhibited status. It is ignored for the TLDs which don't support it.",
+			},
+			"on_destroy": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      domainDestroyAbandon,
+				ValidateFunc: validateDomainDestroyMode,
+				Description:  "What to do with the domain when the resource is destroyed: 'abandon' only removes it from the state, 'disable_autorenew' disables its autorenewal so that it expires, and 'release' deletes it when the registry allows it, or disables its autorenewal otherwise.",
+			},
+			"owner_change": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The status of the last change of the owner of the domain by this resource",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The status of the owner change, 'done' once completed",
+						},
+						"pending": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the owner change is still in progress",
+						},
+						"confirmation_required": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the owner change waits for the confirmation of some contacts by email",
+						},
+						"pending_confirmations": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "The email addresses which have not confirmed the owner change yet",
+						},
+					},
+				},
+			},
 		},
-		Timeouts: &schema.ResourceTimeout{Default: schema.DefaultTimeout(1 * time.Minute)},
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(1 * time.Minute),
+			// The owner changes wait for the confirmation of the
+			// contacts by email
+			Update: schema.DefaultTimeout(10 * time.Minute),
+		},
+	}
+	for key, s := range domainMetadataSchema() {
+		r.Schema[key] = s
 	}
+	return r
 }
 
 func contactSchema(optional bool) *schema.Schema {
@@ -145,13 +229,81 @@ func contactSchema(optional bool) *schema.Schema {
 	}
 }
 
+// domainAPIFields maps the fields of the Gandi API errors to the
+// attributes of the domain, when their names differ
+var domainAPIFields = map[string]string{
+	"fqdn":       "name",
+	"bill":       "billing",
+	"given":      "given_name",
+	"family":     "family_name",
+	"streetaddr": "street_addr",
+	"orgname":    "organisation",
+	"extra":      "extra_parameters",
+}
+
+// expandDomainOrder returns the order of the registration of a domain
+// from its ResourceData or its ResourceDiff.
+func expandDomainOrder(d interface{ Get(string) interface{} }) domainOrder {
+	order := domainOrder{
+		duration:      d.Get("duration").(int),
+		maxPrice:      d.Get("max_price").(float64),
+		acceptPremium: d.Get("accept_premium").(bool),
+	}
+	if order.duration == 0 {
+		order.duration = 1
+	}
+	return order
+}
+
+// suppressRegisteredDomainDiff suppresses the diff of the attributes
+// which are only used when the domain is registered, since changing
+// them afterwards has no effect.
+func suppressRegisteredDomainDiff(k, old, new string, d *schema.ResourceData) bool {
+	return d.Id() != ""
+}
+
+// resourceDomainCustomizeDiff fails the plan of a domain which can't be
+// registered, because it isn't available or costs too much.
+func resourceDomainCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" {
+		if d.HasChange("owner") {
+			return d.SetNewComputed("owner_change")
+		}
+		return nil
+	}
+	if !d.NewValueKnown("name") || !d.NewValueKnown("currency") {
+		return nil
+	}
+	fqdn := d.Get("name").(string)
+	product, currency, err := checkDomain(ctx, meta.(*clients).API, fqdn, d.Get("currency").(string))
+	if err != nil {
+		return err
+	}
+	return expandDomainOrder(d).check(product, currency)
+}
+
 func resourceDomainCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*clients).Domain
+	c := meta.(*clients)
 
 	fqdn := d.Get("name").(string)
+	// The availability and the price are checked again, since they may
+	// have changed since the plan
+	order := expandDomainOrder(d)
+	product, currency, err := checkDomain(ctx, meta.(*clients).API, fqdn, d.Get("currency").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err = order.check(product, currency); err != nil {
+		return diag.FromErr(err)
+	}
+
 	d.SetId(fqdn)
+	meta.(*clients).Cache.invalidateDomain(fqdn)
 	request := domain.CreateRequest{FQDN: fqdn,
-		Owner: expandContact(d.Get("owner")),
+		Owner:          expandContact(d.Get("owner")),
+		Duration:       order.duration,
+		Currency:       d.Get("currency").(string),
+		EnforcePremium: product.Premium,
 	}
 
 	if billing, ok := d.GetOk("billing"); ok {
@@ -171,18 +323,23 @@ func resourceDomainCreate(ctx context.Context, d *schema.ResourceData, meta inte
 		request.Nameservers = expandArray(nameservers.([]interface{}))
 	}
 
-	if err := client.CreateDomain(request); err != nil {
-		return diag.FromErr(err)
+	// The apiClient keeps the field errors of the invalid contacts
+	if err := c.API.Post(ctx, "domain/domains", request, nil); err != nil {
+		return diagFromRequestError(err, resourceDomain().Schema, domainAPIFields)
 	}
 
 	if autorenew, ok := d.GetOk("autorenew"); ok {
-		if err := client.SetAutoRenew(fqdn, autorenew.(bool)); err != nil {
-			return diag.FromErr(err)
+		if err := c.callNonIdempotent(ctx, func() error { return c.Domain.SetAutoRenew(fqdn, autorenew.(bool)) }); err != nil {
+			return diagFromRequestError(err, resourceDomain().Schema, domainAPIFields)
 		}
 	}
 
-	err := resource.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-		if _, err := client.GetDomain(fqdn); err != nil {
+	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		err := c.call(ctx, func() error {
+			_, err := c.Domain.GetDomain(fqdn)
+			return err
+		})
+		if err != nil {
 			return resource.RetryableError(err)
 		}
 		return nil
@@ -193,85 +350,195 @@ func resourceDomainCreate(ctx context.Context, d *schema.ResourceData, meta inte
 
 	if t, ok := d.GetOk("tags"); ok {
 		tags := expandArray(t.([]interface{}))
-		if err := client.SetTags(fqdn, tags); err != nil {
-			return diag.FromErr(err)
+		if err := c.call(ctx, func() error { return c.Domain.SetTags(fqdn, tags) }); err != nil {
+			return diagFromRequestError(err, resourceDomain().Schema, domainAPIFields)
 		}
 	}
 
-	return diag.FromErr(resourceDomainRead(d, meta))
+	diags := setTransferLock(ctx, meta, fqdn, d.Get("transfer_lock").(bool))
+	if diags.HasError() {
+		return diags
+	}
+	return append(diags, resourceDomainRead(ctx, d, meta)...)
+}
+
+// transferLockStatus is the status of the domains locked against
+// transfers
+const transferLockStatus = "clientTransferProhibited"
+
+func transferLocked(details domain.Details) bool {
+	for _, status := range details.Status {
+		if status == transferLockStatus {
+			return true
+		}
+	}
+	return false
 }
 
-func resourceDomainRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*clients).Domain
+// setTransferLock locks or unlocks the domain against transfers. A
+// warning is returned if the TLD doesn't support it.
+func setTransferLock(ctx context.Context, meta interface{}, fqdn string, locked bool) diag.Diagnostics {
+	c := meta.(*clients)
+	var details domain.Details
+	err := c.call(ctx, func() (err error) {
+		details, err = c.Domain.GetDomain(fqdn)
+		return
+	})
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to get the status of %s: %w", fqdn, err))
+	}
+	if details.CanTLDLock != nil && !*details.CanTLDLock {
+		if !locked {
+			return nil
+		}
+		return diag.Diagnostics{{
+			Severity:      diag.Warning,
+			Summary:       fmt.Sprintf("The domain %s can't be locked against transfers", fqdn),
+			Detail:        fmt.Sprintf("The registry of the TLD %s doesn't support the transfer lock.", details.TLD),
+			AttributePath: cty.GetAttrPath("transfer_lock"),
+		}}
+	}
+	if transferLocked(details) == locked {
+		return nil
+	}
+
+	// go-gandi doesn't allow to update the status of a domain
+	request := map[string]bool{transferLockStatus: locked}
+	meta.(*clients).Cache.invalidateDomain(fqdn)
+	if err = meta.(*clients).API.Patch(ctx, "domain/domains/"+fqdn+"/status", request, nil); err != nil {
+		return diagFromRequestError(fmt.Errorf("failed to set the transfer lock of %s: %w", fqdn, err), resourceDomain().Schema, nil)
+	}
+	return nil
+}
+
+func resourceDomainRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cache := meta.(*clients).Cache
 	fqdn := d.Id()
-	response, err := client.GetDomain(fqdn)
+	response, err := cache.domainDetails(ctx, fqdn)
 	if err != nil {
 		d.SetId("")
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(response.FQDN)
 	if err = d.Set("name", response.FQDN); err != nil {
-		return fmt.Errorf("failed to set name for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set name for %s: %w", d.Id(), err))
 	}
 
 	// Nameservers are only set when livedns is not used. When
 	// livedns is used, this nameservers list is managed by Gandi:
 	// the user should not have to care about them.
-	livedns, err := client.GetLiveDNS(fqdn)
+	livedns, err := cache.domainLiveDNS(ctx, fqdn)
 	if err != nil {
 		d.SetId("")
-		return err
+		return diag.FromErr(err)
 	}
 	if livedns.Current != "livedns" {
 		if err = d.Set("nameservers", response.Nameservers); err != nil {
-			return fmt.Errorf("failed to set nameservers for %s: %w", d.Id(), err)
+			return diag.FromErr(fmt.Errorf("failed to set nameservers for %s: %w", d.Id(), err))
 		}
 	}
 	if err = d.Set("autorenew", response.AutoRenew.Enabled); err != nil {
-		return fmt.Errorf("failed to set autorenew for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set autorenew for %s: %w", d.Id(), err))
+	}
+	if err = setDomainMetadata(d, response); err != nil {
+		return diag.FromErr(err)
+	}
+	// The transfer lock is kept as configured for the TLDs which don't
+	// support it
+	if response.CanTLDLock == nil || *response.CanTLDLock {
+		if err = d.Set("transfer_lock", transferLocked(response)); err != nil {
+			return diag.FromErr(fmt.Errorf("failed to set transfer_lock for %s: %w", d.Id(), err))
+		}
 	}
 	if response.Contacts != nil {
 		if response.Contacts.Owner != nil {
 			if err = d.Set("owner", flattenContact(response.Contacts.Owner)); err != nil {
-				return fmt.Errorf("failed to set the owner for %s: %w", d.Id(), err)
+				return diag.FromErr(fmt.Errorf("failed to set the owner for %s: %w", d.Id(), err))
 			}
 		}
 		if response.Contacts.Admin != nil {
 			if err = d.Set("admin", flattenContact(response.Contacts.Admin)); err != nil {
-				return fmt.Errorf("failed to set the admin for %s: %w", d.Id(), err)
+				return diag.FromErr(fmt.Errorf("failed to set the admin for %s: %w", d.Id(), err))
 			}
 		}
 		if response.Contacts.Billing != nil {
 			if err = d.Set("billing", flattenContact(response.Contacts.Billing)); err != nil {
-				return fmt.Errorf("failed to set the billing contact for %s: %w", d.Id(), err)
+				return diag.FromErr(fmt.Errorf("failed to set the billing contact for %s: %w", d.Id(), err))
 			}
 		}
 		if response.Contacts.Tech != nil {
 			if err = d.Set("tech", flattenContact(response.Contacts.Tech)); err != nil {
-				return fmt.Errorf("failed to set the tech contact for %s: %w", d.Id(), err)
+				return diag.FromErr(fmt.Errorf("failed to set the tech contact for %s: %w", d.Id(), err))
 			}
 		}
 	}
 
-	tags, err := client.GetTags(fqdn)
+	tags, err := cache.domainTags(ctx, fqdn)
 	if err != nil {
 		d.SetId("")
-		return err
+		return diag.FromErr(err)
 	}
 	if len(tags) != 0 {
 		if err = d.Set("tags", response.Tags); err != nil {
-			return fmt.Errorf("failed to set tags for %s: %w", d.Id(), err)
+			return diag.FromErr(fmt.Errorf("failed to set tags for %s: %w", d.Id(), err))
 		}
 	}
 
+	// on_destroy isn't set on import
+	if _, ok := d.GetOk("on_destroy"); !ok {
+		if err = d.Set("on_destroy", domainDestroyAbandon); err != nil {
+			return diag.FromErr(fmt.Errorf("failed to set on_destroy for %s: %w", d.Id(), err))
+		}
+	}
+
+	// The owner change is only read once this resource changed the
+	// owner, since it requires more rights than the domain itself
+	var change *ownerChange
+	if (d.HasChange("owner") && !d.IsNewResource()) || len(d.Get("owner_change").([]interface{})) > 0 {
+		change, err = cache.domainOwnerChange(ctx, fqdn)
+		if requestErrorStatus(err) == http.StatusForbidden {
+			return diag.Diagnostics{{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("The owner change of %s could not be read", fqdn),
+				Detail:   fmt.Sprintf("The owner_change attribute is kept as is: %s", err),
+			}}
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if err = d.Set("owner_change", flattenOwnerChange(change)); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set owner_change for %s: %w", d.Id(), err))
+	}
+
 	return nil
 }
 
-func resourceDomainUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*clients).Domain
+// resetChanges sets the attributes changed by the plan back to their
+// prior values, so that the state of a failed update only records what
+// was set afterwards.
+func resetChanges(d *schema.ResourceData, s map[string]*schema.Schema) error {
+	for key := range s {
+		if !d.HasChange(key) {
+			continue
+		}
+		old, _ := d.GetChange(key)
+		if err := d.Set(key, old); err != nil {
+			return fmt.Errorf("failed to reset %s for %s: %w", key, d.Id(), err)
+		}
+	}
+	return nil
+}
+
+func resourceDomainUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
+	fqdn := d.Get("name").(string)
+	c.Cache.invalidateDomain(fqdn)
 
-	if d.HasChanges("owner") {
-		return fmt.Errorf("domain owner contact update is currently not supported")
+	if d.HasChange("owner") {
+		if diags := resourceDomainChangeOwner(ctx, d, meta); diags.HasError() {
+			return diags
+		}
 	}
 
 	if d.HasChanges("admin", "tech", "billing") {
@@ -295,36 +562,168 @@ func resourceDomainUpdate(d *schema.ResourceData, meta interface{}) error {
 			}
 		}
 
-		if err := client.SetContacts(d.Get("name").(string), contacts); err != nil {
-			return err
+		if err := c.API.Patch(ctx, "domain/domains/"+fqdn+"/contacts", contacts, nil); err != nil {
+			return diagFromRequestError(err, resourceDomain().Schema, domainAPIFields)
 		}
 
 	}
 	if d.HasChange("autorenew") {
-		if err := client.SetAutoRenew(d.Get("name").(string), d.Get("autorenew").(bool)); err != nil {
-			return err
+		if err := c.callNonIdempotent(ctx, func() error { return c.Domain.SetAutoRenew(fqdn, d.Get("autorenew").(bool)) }); err != nil {
+			return diagFromRequestError(err, resourceDomain().Schema, domainAPIFields)
 		}
 	}
 
 	if d.HasChange("nameservers") {
 		ns := expandArray(d.Get("nameservers").([]interface{}))
-		if err := client.UpdateNameServers(d.Get("name").(string), ns); err != nil {
-			return err
+		if err := c.call(ctx, func() error { return c.Domain.UpdateNameServers(fqdn, ns) }); err != nil {
+			return diagFromRequestError(err, resourceDomain().Schema, domainAPIFields)
 		}
 	}
 
 	if d.HasChange("tags") {
 		tags := expandArray(d.Get("tags").([]interface{}))
-		if err := client.SetTags(d.Get("name").(string), tags); err != nil {
-			return err
+		if err := c.call(ctx, func() error { return c.Domain.SetTags(fqdn, tags) }); err != nil {
+			return diagFromRequestError(err, resourceDomain().Schema, domainAPIFields)
 		}
 	}
 
-	return resourceDomainRead(d, meta)
+	var diags diag.Diagnostics
+	if d.HasChange("transfer_lock") {
+		diags = setTransferLock(ctx, meta, fqdn, d.Get("transfer_lock").(bool))
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	return append(diags, resourceDomainRead(ctx, d, meta)...)
 }
 
-// The Gandi API doesn't presently support deleting domains
-func resourceDomainDelete(d *schema.ResourceData, _ interface{}) error {
-	d.SetId("")
+// resourceDomainChangeOwner submits the change of the owner of the
+// domain, or resumes the one already submitted, and waits until it
+// completes. The owner change can require the confirmation of the
+// contacts by email: when it is still pending once the update timeout
+// is reached, the previous state is kept along with the owner change,
+// so that the next apply waits for it again.
+func resourceDomainChangeOwner(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*clients).API
+	fqdn := d.Get("name").(string)
+	owner := expandContact(d.Get("owner"))
+
+	change, err := getOwnerChange(ctx, client, fqdn)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if change == nil || !change.pending() || !change.sameOwner(owner) {
+		if err = changeOwner(ctx, client, fqdn, owner); err != nil {
+			return diagFromRequestError(fmt.Errorf("failed to change the owner of %s: %w", fqdn, err), resourceDomain().Schema, domainAPIFields)
+		}
+	} else {
+		log.Printf("[DEBUG] resuming the pending owner change of %s", fqdn)
+	}
+
+	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
+		change, err = getOwnerChange(ctx, client, fqdn)
+		switch {
+		case err != nil:
+			return resource.NonRetryableError(err)
+		case change == nil || change.pending():
+			return resource.RetryableError(fmt.Errorf("the owner change of %s is still pending", fqdn))
+		case change.Status != ownerChangeDone:
+			return resource.NonRetryableError(fmt.Errorf("the owner change of %s failed with the status %s", fqdn, change.Status))
+		}
+		return nil
+	})
+	if err != nil {
+		if resetErr := resetChanges(d, resourceDomain().Schema); resetErr != nil {
+			return diag.FromErr(resetErr)
+		}
+		if change != nil {
+			if setErr := d.Set("owner_change", flattenOwnerChange(change)); setErr != nil {
+				return diag.FromErr(fmt.Errorf("failed to set owner_change for %s: %w", fqdn, setErr))
+			}
+		}
+		if change != nil && change.pending() {
+			if emails := change.pendingConfirmations(); len(emails) > 0 {
+				return diag.Errorf("the owner change of %s is waiting for the confirmation of %s: apply again once it is confirmed", fqdn, strings.Join(emails, ", "))
+			}
+		}
+		return diag.FromErr(err)
+	}
 	return nil
 }
+
+// The modes of on_destroy
+const (
+	domainDestroyAbandon          = "abandon"
+	domainDestroyDisableAutorenew = "disable_autorenew"
+	domainDestroyRelease          = "release"
+)
+
+func validateDomainDestroyMode(val interface{}, key string) (warns []string, errs []error) {
+	switch v := val.(string); v {
+	case domainDestroyAbandon, domainDestroyDisableAutorenew, domainDestroyRelease:
+	default:
+		errs = append(errs, fmt.Errorf("%q must be one of %s, %s or %s. Got %s", key, domainDestroyAbandon, domainDestroyDisableAutorenew, domainDestroyRelease, v))
+	}
+	return
+}
+
+// resourceDomainDelete stops the billing of the domain according to
+// on_destroy. Most registries don't allow to delete a domain: a domain
+// which can't be released is kept until its expiration, with its
+// autorenewal disabled.
+func resourceDomainDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
+	fqdn := d.Id()
+
+	var diags diag.Diagnostics
+	switch d.Get("on_destroy").(string) {
+	case domainDestroyRelease:
+		// A domain which is no longer in the account has already been
+		// released
+		_, err := c.Cache.domainDetails(ctx, fqdn)
+		c.Cache.invalidateDomain(fqdn)
+		if requestErrorStatus(err) == http.StatusNotFound {
+			break
+		}
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("failed to get the domain %s: %w", fqdn, err))
+		}
+		// The deletion of a domain is only supported by some registries,
+		// see https://api.gandi.net/docs/domains/: depending on the
+		// registry, the other ones answer 403, 405 or 501, in which case
+		// the autorenewal is disabled instead.
+		err = c.API.Delete(ctx, "domain/domains/"+fqdn, nil)
+		if err == nil {
+			break
+		}
+		switch requestErrorStatus(err) {
+		case http.StatusForbidden, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		default:
+			return diag.FromErr(fmt.Errorf("failed to release %s: %w", fqdn, err))
+		}
+		if err := c.callNonIdempotent(ctx, func() error { return c.Domain.SetAutoRenew(fqdn, false) }); err != nil {
+			return diag.FromErr(fmt.Errorf("failed to disable the autorenewal of %s: %w", fqdn, err))
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("The domain %s could not be released", fqdn),
+			Detail:   fmt.Sprintf("The registry doesn't allow to delete the domain (%s). Its autorenewal has been disabled instead: it stays registered until it expires.", err),
+		})
+	case domainDestroyDisableAutorenew:
+		c.Cache.invalidateDomain(fqdn)
+		if err := c.callNonIdempotent(ctx, func() error { return c.Domain.SetAutoRenew(fqdn, false) }); err != nil {
+			return diag.FromErr(fmt.Errorf("failed to disable the autorenewal of %s: %w", fqdn, err))
+		}
+	default:
+		if d.Get("autorenew").(bool) {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("The domain %s is still renewed and billed", fqdn),
+				Detail:   "The domain has been removed from the state, but it is still registered and its autorenewal is enabled. Set on_destroy to disable_autorenew or release to stop its billing.",
+			})
+		}
+	}
+	d.SetId("")
+	return diags
+}
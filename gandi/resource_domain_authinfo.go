@@ -0,0 +1,99 @@
+package gandi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-gandi/go-gandi/domain"
+	"github.com/go-gandi/go-gandi/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceDomainAuthInfo() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDomainAuthInfoCreate,
+		ReadContext:   resourceDomainAuthInfoRead,
+		DeleteContext: resourceDomainAuthInfoDelete,
+		Schema: map[string]*schema.Schema{
+			"domain": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The FQDN of the domain",
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Arbitrary values which reset the authorization code when they change",
+			},
+			"authinfo": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The authorization code of the domain, to transfer it to another registrar",
+			},
+			"expires_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The expiration date of the authorization code, if any",
+			},
+		},
+	}
+}
+
+// setAuthInfo sets the authinfo attributes from the details of a domain
+func setAuthInfo(d *schema.ResourceData, details domain.Details) error {
+	if err := d.Set("authinfo", details.AuthInfo); err != nil {
+		return fmt.Errorf("failed to set authinfo for %s: %w", d.Id(), err)
+	}
+	expiresAt := ""
+	if details.Dates != nil && details.Dates.AuthInfoExpiresAt != nil {
+		expiresAt = details.Dates.AuthInfoExpiresAt.UTC().Format(time.RFC3339)
+	}
+	if err := d.Set("expires_at", expiresAt); err != nil {
+		return fmt.Errorf("failed to set expires_at for %s: %w", d.Id(), err)
+	}
+	return nil
+}
+
+// resourceDomainAuthInfoCreate resets the authorization code of the
+// domain: the previous one can no longer be used.
+func resourceDomainAuthInfoCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	fqdn := d.Get("domain").(string)
+
+	// go-gandi doesn't allow to reset the authinfo
+	meta.(*clients).Cache.invalidateDomain(fqdn)
+	if err := meta.(*clients).API.Patch(ctx, "domain/domains/"+fqdn+"/authinfo", nil, nil); err != nil {
+		return diagFromRequestError(fmt.Errorf("failed to reset the authinfo of %s: %w", fqdn, err), resourceDomainAuthInfo().Schema, nil)
+	}
+	d.SetId(fqdn)
+	return resourceDomainAuthInfoRead(ctx, d, meta)
+}
+
+func resourceDomainAuthInfoRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	details, err := meta.(*clients).Cache.domainDetails(ctx, d.Id())
+	if err != nil {
+		if requestError, ok := err.(*types.RequestError); ok && requestError.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	if err = d.Set("domain", details.FQDN); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set domain for %s: %w", d.Id(), err))
+	}
+	if err = setAuthInfo(d, details); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+// The authorization code is only removed from the state
+func resourceDomainAuthInfoDelete(ctx context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	d.SetId("")
+	return nil
+}
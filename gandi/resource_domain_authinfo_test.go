@@ -0,0 +1,47 @@
+package gandi
+
+import (
+	"testing"
+)
+
+func TestOfflineDomainAuthInfo_basic(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addDomain("example.com")
+	p := newTestOfflineProvider(t, api)
+
+	config := map[string]interface{}{
+		"domain":   "example.com",
+		"triggers": map[string]interface{}{"rotation": "1"},
+	}
+	state := p.apply("gandi_domain_authinfo", nil, config)
+	authinfo := state.Attributes["authinfo"]
+	if authinfo == "" || authinfo != api.domains["example.com"].details.AuthInfo {
+		t.Fatalf("expected the authinfo to be reset and exposed, got %q", authinfo)
+	}
+	if state.Attributes["expires_at"] == "" {
+		t.Fatalf("expected the expiration of the authinfo to be set")
+	}
+	p.planEmpty("gandi_domain_authinfo", state, config)
+
+	data := p.readDataSource("gandi_domain_authinfo", map[string]interface{}{"domain": "example.com"})
+	testCheckAttributes(t, data, map[string]string{"authinfo": authinfo})
+
+	// Changing the triggers rotates the authinfo
+	config["triggers"] = map[string]interface{}{"rotation": "2"}
+	if diff, diags := p.plan("gandi_domain_authinfo", state, config); diags.HasError() || !diff.RequiresNew() {
+		t.Fatalf("expected the authinfo to be replaced, got %v", diags)
+	}
+	p.destroy("gandi_domain_authinfo", state)
+	state = p.apply("gandi_domain_authinfo", nil, config)
+	if rotated := state.Attributes["authinfo"]; rotated == authinfo || rotated != api.domains["example.com"].details.AuthInfo {
+		t.Fatalf("expected the authinfo to be rotated, got %q after %q", rotated, authinfo)
+	}
+}
+
+func TestOfflineDataDomainAuthInfo_unknown(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	p := newTestOfflineProvider(t, api)
+	if _, diags := p.readDataSourceWithDiags("gandi_domain_authinfo", map[string]interface{}{"domain": "unknown.com"}); !diags.HasError() {
+		t.Fatalf("reading the authinfo of an unknown domain should fail")
+	}
+}
@@ -0,0 +1,177 @@
+package gandi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-gandi/go-gandi/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceDomainRenewal() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDomainRenewalCreate,
+		ReadContext:   resourceDomainRenewalRead,
+		UpdateContext: resourceDomainRenewalUpdate,
+		DeleteContext: resourceDomainRenewalDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceDomainRenewalImport,
+		},
+		CustomizeDiff: resourceDomainRenewalCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"domain": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The FQDN of the domain",
+			},
+			"duration": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validateDomainDuration,
+				Description:  "The number of years the domain is renewed for",
+			},
+			"renewal_window_days": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validateNonNegativeInt,
+				Description:  "The domain is renewed again when it expires in fewer days, unless it was already renewed within the current window, since the expiration date only moves once the renewal is processed. It must be shorter than the duration of the renewal. It is only renewed when the resource is created if this is not set.",
+			},
+			"expires_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The expiration date of the domain",
+			},
+			"renewed_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The date of the last renewal of the domain by this resource, or the date of its import",
+			},
+		},
+	}
+}
+
+// renewalDue returns whether the domain expiring at expiresAt must be
+// renewed, according to the renewal window in days. Renewals are
+// processed asynchronously, so the expiration date may not have moved
+// yet after the last renewal: the domain isn't renewed again when it
+// was already renewed within the current window.
+func renewalDue(expiresAt, renewedAt string, window int) bool {
+	if window == 0 || expiresAt == "" {
+		return false
+	}
+	expires, err := time.Parse(time.RFC3339, expiresAt)
+	if err != nil {
+		return false
+	}
+	windowStart := expires.Add(-time.Duration(window) * 24 * time.Hour)
+	if renewed, err := time.Parse(time.RFC3339, renewedAt); err == nil && renewed.After(windowStart) {
+		return false
+	}
+	return time.Now().After(windowStart)
+}
+
+// resourceDomainRenewalCustomizeDiff plans a renewal once the domain
+// expires within the renewal window
+func resourceDomainRenewalCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	// The domain would otherwise still be within the window right
+	// after its renewal, and be renewed on every apply
+	if d.NewValueKnown("duration") && d.NewValueKnown("renewal_window_days") {
+		duration, window := d.Get("duration").(int), d.Get("renewal_window_days").(int)
+		if window >= duration*365 {
+			return fmt.Errorf("renewal_window_days (%d) must be shorter than the duration of the renewal (%d years)", window, duration)
+		}
+	}
+	if d.Id() == "" {
+		return nil
+	}
+	if renewalDue(d.Get("expires_at").(string), d.Get("renewed_at").(string), d.Get("renewal_window_days").(int)) {
+		if err := d.SetNewComputed("expires_at"); err != nil {
+			return err
+		}
+		return d.SetNewComputed("renewed_at")
+	}
+	return nil
+}
+
+// renewDomain renews the domain for the duration in years, with the
+// renew endpoint which go-gandi doesn't cover
+func renewDomain(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	fqdn := d.Get("domain").(string)
+	request := struct {
+		Duration int `json:"duration"`
+	}{Duration: d.Get("duration").(int)}
+
+	meta.(*clients).Cache.invalidateDomain(fqdn)
+	if err := meta.(*clients).API.Post(ctx, "domain/domains/"+fqdn+"/renew", request, nil); err != nil {
+		return diagFromRequestError(fmt.Errorf("failed to renew %s: %w", fqdn, err), resourceDomainRenewal().Schema, nil)
+	}
+	if err := d.Set("renewed_at", time.Now().UTC().Format(time.RFC3339)); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set renewed_at for %s: %w", fqdn, err))
+	}
+	return nil
+}
+
+func resourceDomainRenewalCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if diags := renewDomain(ctx, d, meta); diags.HasError() {
+		return diags
+	}
+	d.SetId(d.Get("domain").(string))
+	return resourceDomainRenewalRead(ctx, d, meta)
+}
+
+func resourceDomainRenewalRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	fqdn := d.Id()
+	response, err := meta.(*clients).Cache.domainDetails(ctx, fqdn)
+	if err != nil {
+		if requestError, ok := err.(*types.RequestError); ok && requestError.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	if err = d.Set("domain", response.FQDN); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set domain for %s: %w", d.Id(), err))
+	}
+	expiresAt := ""
+	if response.Dates != nil && response.Dates.RegistryEndsAt != nil {
+		expiresAt = response.Dates.RegistryEndsAt.UTC().Format(time.RFC3339)
+	}
+	if err = d.Set("expires_at", expiresAt); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set expires_at for %s: %w", d.Id(), err))
+	}
+	return nil
+}
+
+// resourceDomainRenewalUpdate renews the domain again once it expires
+// within the renewal window. Changing the duration or the window
+// doesn't renew the domain by itself.
+func resourceDomainRenewalUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	expiresAt, _ := d.GetChange("expires_at")
+	renewedAt, _ := d.GetChange("renewed_at")
+	if renewalDue(expiresAt.(string), renewedAt.(string), d.Get("renewal_window_days").(int)) {
+		if diags := renewDomain(ctx, d, meta); diags.HasError() {
+			return diags
+		}
+	}
+	return resourceDomainRenewalRead(ctx, d, meta)
+}
+
+// resourceDomainRenewalImport imports the renewal of a domain. The API
+// doesn't expose the date of the last renewal: the import counts as
+// one, so that a domain which may already have been renewed within the
+// current window isn't renewed again.
+func resourceDomainRenewalImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if err := d.Set("renewed_at", time.Now().UTC().Format(time.RFC3339)); err != nil {
+		return nil, fmt.Errorf("failed to set renewed_at for %s: %w", d.Id(), err)
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// A renewal can't be canceled: it is only removed from the state
+func resourceDomainRenewalDelete(ctx context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	d.SetId("")
+	return nil
+}
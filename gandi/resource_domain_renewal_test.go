@@ -0,0 +1,133 @@
+package gandi
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOfflineDomainRenewal_basic(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addDomain("example.com")
+	ends := time.Now().UTC().Truncate(time.Second).AddDate(1, 0, 0)
+	api.domains["example.com"].details.Dates.RegistryEndsAt = &ends
+	p := newTestOfflineProvider(t, api)
+
+	config := map[string]interface{}{
+		"domain":              "example.com",
+		"duration":            3,
+		"renewal_window_days": 90,
+	}
+	state := p.apply("gandi_domain_renewal", nil, config)
+	testCheckAttributes(t, state, map[string]string{
+		"id":         "example.com",
+		"expires_at": ends.AddDate(3, 0, 0).Format(time.RFC3339),
+	})
+	if state.Attributes["renewed_at"] == "" {
+		t.Fatalf("expected renewed_at to be set")
+	}
+
+	// The domain isn't renewed again before the renewal window
+	p.planEmpty("gandi_domain_renewal", state, config)
+	config["duration"] = 2
+	state = p.apply("gandi_domain_renewal", state, config)
+	if count := api.requestCount("POST domain/domains/example.com/renew"); count != 1 {
+		t.Fatalf("expected a single renewal, got %d", count)
+	}
+
+	// Once the domain expires within the window, a year later, it is
+	// renewed again
+	ends = time.Now().UTC().Truncate(time.Second).AddDate(0, 0, 30)
+	api.domains["example.com"].details.Dates.RegistryEndsAt = &ends
+	state.Attributes["renewed_at"] = time.Now().UTC().AddDate(-1, 0, 0).Format(time.RFC3339)
+	p = newTestOfflineProvider(t, api)
+	state = p.refresh("gandi_domain_renewal", state)
+	state = p.apply("gandi_domain_renewal", state, config)
+	testCheckAttributes(t, state, map[string]string{
+		"expires_at": ends.AddDate(2, 0, 0).Format(time.RFC3339),
+	})
+	if count := api.requestCount("POST domain/domains/example.com/renew"); count != 2 {
+		t.Fatalf("expected the domain to be renewed within the window, got %d renewals", count)
+	}
+	p.planEmpty("gandi_domain_renewal", state, config)
+}
+
+// TestOfflineDomainRenewal_pending checks a renewal which isn't
+// processed yet isn't sent again.
+func TestOfflineDomainRenewal_pending(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addDomain("example.com")
+	api.pendingRenewals = true
+	ends := time.Now().UTC().Truncate(time.Second).AddDate(0, 0, 30)
+	api.domains["example.com"].details.Dates.RegistryEndsAt = &ends
+	p := newTestOfflineProvider(t, api)
+
+	config := map[string]interface{}{
+		"domain":              "example.com",
+		"duration":            1,
+		"renewal_window_days": 90,
+	}
+	state := p.apply("gandi_domain_renewal", nil, config)
+	testCheckAttributes(t, state, map[string]string{"expires_at": ends.Format(time.RFC3339)})
+	p = newTestOfflineProvider(t, api)
+	state = p.refresh("gandi_domain_renewal", state)
+	p.planEmpty("gandi_domain_renewal", state, config)
+	if count := api.requestCount("POST domain/domains/example.com/renew"); count != 1 {
+		t.Fatalf("expected a single renewal while it is pending, got %d", count)
+	}
+}
+
+// TestOfflineDomainRenewal_import checks an imported domain, which may
+// already have been renewed within the current window, isn't renewed
+// again.
+func TestOfflineDomainRenewal_import(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addDomain("example.com")
+	ends := time.Now().UTC().Truncate(time.Second).AddDate(0, 0, 30)
+	api.domains["example.com"].details.Dates.RegistryEndsAt = &ends
+	p := newTestOfflineProvider(t, api)
+
+	state := p.importState("gandi_domain_renewal", "example.com")
+	if state.Attributes["renewed_at"] == "" {
+		t.Fatalf("expected renewed_at to be set on import")
+	}
+	config := map[string]interface{}{
+		"domain":              "example.com",
+		"duration":            1,
+		"renewal_window_days": 90,
+	}
+	state = p.apply("gandi_domain_renewal", state, config)
+	p.planEmpty("gandi_domain_renewal", state, config)
+	if count := api.requestCount("POST domain/domains/example.com/renew"); count != 0 {
+		t.Fatalf("expected the imported domain not to be renewed, got %d renewals", count)
+	}
+}
+
+func TestOfflineDomainRenewal_errors(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addDomain("example.com")
+	p := newTestOfflineProvider(t, api)
+
+	if _, diags := p.applyWithDiags("gandi_domain_renewal", nil, map[string]interface{}{
+		"domain":   "example.com",
+		"duration": 10,
+	}); !diags.HasError() {
+		t.Fatalf("renewing a domain beyond 10 years should fail")
+	}
+	if _, diags := p.applyWithDiags("gandi_domain_renewal", nil, map[string]interface{}{
+		"domain":   "unknown.com",
+		"duration": 1,
+	}); !diags.HasError() {
+		t.Fatalf("renewing an unknown domain should fail")
+	}
+	renewals := api.requestCount("POST domain/domains/example.com/renew")
+	if _, diags := p.applyWithDiags("gandi_domain_renewal", nil, map[string]interface{}{
+		"domain":              "example.com",
+		"duration":            1,
+		"renewal_window_days": 365,
+	}); !diags.HasError() {
+		t.Fatalf("a renewal window as long as the renewal should be rejected")
+	}
+	if count := api.requestCount("POST domain/domains/example.com/renew"); count != renewals {
+		t.Fatalf("expected no renewal, got %d", count-renewals)
+	}
+}
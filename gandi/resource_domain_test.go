@@ -2,10 +2,15 @@ package gandi
 
 import (
 	"fmt"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func TestAccDomain_basic(t *testing.T) {
@@ -81,3 +86,450 @@ func testAccConfig(resourceName, domainName string, tags string) string {
             }
 	`, resourceName, domainName, tags)
 }
+
+func testOfflineContact(email string) []interface{} {
+	return []interface{}{
+		map[string]interface{}{
+			"city":         "Paris",
+			"country":      "FR",
+			"email":        email,
+			"family_name":  "Tests",
+			"given_name":   "Gandi",
+			"organisation": "gandi_terraform_provider_tests",
+			"phone":        "+33.606060606",
+			"street_addr":  "Paris",
+			"type":         "company",
+			"zip":          "75000",
+		},
+	}
+}
+
+func testOfflineDomainConfig(extra map[string]interface{}) map[string]interface{} {
+	config := map[string]interface{}{
+		"name":  "example.com",
+		"owner": testOfflineContact("owner@example.com"),
+	}
+	for k, v := range extra {
+		config[k] = v
+	}
+	return config
+}
+
+func TestOfflineDomain_basic(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	p := newTestOfflineProvider(t, api)
+	config := testOfflineDomainConfig(map[string]interface{}{
+		"autorenew": true,
+		"tags":      []interface{}{"tag1"},
+	})
+
+	state := p.apply("gandi_domain", nil, config)
+	testCheckAttributes(t, state, map[string]string{
+		"id":        "example.com",
+		"name":      "example.com",
+		"autorenew": "true",
+		"tags.#":    "1",
+		"tags.0":    "tag1",
+		"owner.#":   "1",
+		"admin.#":   "1",
+		"billing.#": "1",
+		"tech.#":    "1",
+	})
+	p.planEmpty("gandi_domain", state, config)
+
+	imported := p.importState("gandi_domain", "example.com")
+	testCheckAttributes(t, imported, map[string]string{
+		"name":      "example.com",
+		"autorenew": "true",
+		"tags.0":    "tag1",
+	})
+
+	p.destroy("gandi_domain", state)
+	if _, ok := api.domains["example.com"]; !ok {
+		t.Fatalf("the domain can not be deleted and should still exist")
+	}
+}
+
+func TestOfflineDomain_update(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	p := newTestOfflineProvider(t, api)
+
+	state := p.apply("gandi_domain", nil, testOfflineDomainConfig(map[string]interface{}{
+		"tags": []interface{}{"tag1"},
+	}))
+	config := testOfflineDomainConfig(map[string]interface{}{
+		"autorenew": true,
+		"tags":      []interface{}{"tag2"},
+		"admin":     testOfflineContact("admin@example.com"),
+	})
+	state = p.apply("gandi_domain", state, config)
+	testCheckAttributes(t, state, map[string]string{
+		"autorenew": "true",
+		"tags.0":    "tag2",
+	})
+	if email := api.domains["example.com"].details.Contacts.Admin.Email; email != "admin@example.com" {
+		t.Fatalf("the admin contact should have been updated, got %s", email)
+	}
+	p.planEmpty("gandi_domain", state, config)
+}
+
+func TestOfflineDomain_onDestroy(t *testing.T) {
+	for _, tc := range []struct {
+		mode      string
+		tld       string
+		autorenew bool
+		gone      bool
+		deleted   bool
+		warning   bool
+		failed    bool
+	}{
+		{mode: "abandon", tld: "com", autorenew: true, warning: true},
+		{mode: "abandon", tld: "com", autorenew: false},
+		{mode: "disable_autorenew", tld: "com", autorenew: true},
+		{mode: "release", tld: "fr", autorenew: true, deleted: true},
+		{mode: "release", tld: "com", autorenew: true, warning: true},
+		{mode: "release", tld: "net", autorenew: true, warning: true},
+		{mode: "release", tld: "org", autorenew: true, warning: true},
+		{mode: "release", tld: "com", autorenew: true, gone: true, deleted: true},
+		{mode: "release", tld: "info", autorenew: true, failed: true},
+	} {
+		api := newFakeGandiAPI(t)
+		api.releasableTLDs["fr"] = true
+		api.releaseErrors["net"] = http.StatusMethodNotAllowed
+		api.releaseErrors["org"] = http.StatusNotImplemented
+		api.releaseErrors["info"] = http.StatusNotFound
+		p := newTestOfflineProvider(t, api)
+		fqdn := "example." + tc.tld
+		state := p.apply("gandi_domain", nil, testOfflineDomainConfig(map[string]interface{}{
+			"name":       fqdn,
+			"autorenew":  tc.autorenew,
+			"on_destroy": tc.mode,
+		}))
+
+		if tc.gone {
+			delete(api.domains, fqdn)
+		}
+
+		diags := p.destroyWithDiags("gandi_domain", state)
+		if tc.failed {
+			if !diags.HasError() {
+				t.Errorf("%s of %s: expected the destroy to fail", tc.mode, fqdn)
+			}
+			continue
+		}
+		if diags.HasError() {
+			t.Fatalf("%s of %s: failed to destroy the domain: %v", tc.mode, fqdn, diags)
+		}
+		if warning := len(diags) > 0; warning != tc.warning {
+			t.Errorf("%s of %s: expected a warning to be %v, got %v", tc.mode, fqdn, tc.warning, diags)
+		}
+		d, ok := api.domains[fqdn]
+		switch {
+		case ok == tc.deleted:
+			t.Errorf("%s of %s: expected the domain to be deleted to be %v", tc.mode, fqdn, tc.deleted)
+		case ok && tc.mode != "abandon" && *d.details.AutoRenew.Enabled:
+			t.Errorf("%s of %s: expected the autorenewal to be disabled", tc.mode, fqdn)
+		case ok && tc.mode == "abandon" && *d.details.AutoRenew.Enabled != tc.autorenew:
+			t.Errorf("%s of %s: expected the autorenewal to be kept", tc.mode, fqdn)
+		}
+	}
+}
+
+func TestOfflineDomain_ownerChange(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	p := newTestOfflineProvider(t, api)
+
+	state := p.apply("gandi_domain", nil, testOfflineDomainConfig(nil))
+	testCheckAttributes(t, state, map[string]string{"owner_change.#": "0"})
+
+	api.ownerChangePolls = 1
+	config := testOfflineDomainConfig(map[string]interface{}{
+		"owner": testOfflineContact("new-owner@example.com"),
+	})
+	state = p.apply("gandi_domain", state, config)
+	testCheckAttributes(t, state, map[string]string{
+		"owner_change.#":                         "1",
+		"owner_change.0.status":                  "done",
+		"owner_change.0.pending":                 "false",
+		"owner_change.0.confirmation_required":   "false",
+		"owner_change.0.pending_confirmations.#": "0",
+	})
+	if email := api.domains["example.com"].details.Contacts.Owner.Email; email != "new-owner@example.com" {
+		t.Fatalf("the owner should have been changed, got %s", email)
+	}
+	if count := api.requestCount("POST domain/changeowner/example.com"); count != 1 {
+		t.Fatalf("expected the owner change to be submitted once, got %d requests", count)
+	}
+	p.planEmpty("gandi_domain", state, config)
+
+	// The owner change is still read once it is in the state, but a
+	// key which may not read it only gets a warning
+	api.ownerChangeForbidden = true
+	p = newTestOfflineProvider(t, api)
+	refreshed, diags := p.refreshWithDiags("gandi_domain", state)
+	if diags.HasError() || len(diags) != 1 {
+		t.Fatalf("expected a warning, got %v", diags)
+	}
+	testCheckAttributes(t, refreshed, map[string]string{"owner_change.0.status": "done"})
+}
+
+// TestOfflineDomain_ownerChangeNotRead checks the owner changes of a
+// domain whose owner wasn't changed by the resource aren't read.
+func TestOfflineDomain_ownerChangeNotRead(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.ownerChangeForbidden = true
+	p := newTestOfflineProvider(t, api)
+
+	state := p.apply("gandi_domain", nil, testOfflineDomainConfig(nil))
+	p = newTestOfflineProvider(t, api)
+	state = p.refresh("gandi_domain", state)
+	testCheckAttributes(t, state, map[string]string{"owner_change.#": "0"})
+	if count := api.requestCount("GET domain/changeowner/example.com"); count != 0 {
+		t.Fatalf("expected the owner change not to be read, got %d requests", count)
+	}
+}
+
+func TestOfflineDomain_ownerChangePending(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	p := newTestOfflineProvider(t, api)
+	state := p.apply("gandi_domain", nil, testOfflineDomainConfig(nil))
+
+	// The contacts don't confirm the owner change before the timeout
+	api.ownerChangePolls = -1
+	config := testOfflineDomainConfig(map[string]interface{}{
+		"owner":    testOfflineContact("new-owner@example.com"),
+		"timeouts": map[string]interface{}{"update": "1s"},
+	})
+	newState, diags := p.applyWithDiags("gandi_domain", state, config)
+	if !diags.HasError() {
+		t.Fatalf("the owner change should time out while it is pending")
+	}
+	if detail := diags[0].Summary; !strings.Contains(detail, "new-owner@example.com") || !strings.Contains(detail, "owner@example.com") {
+		t.Fatalf("expected the error to list the pending confirmations, got %q", detail)
+	}
+	testCheckAttributes(t, newState, map[string]string{"owner.#": "1"})
+	if owner := p.resource("gandi_domain").Data(newState).Get("owner").(*schema.Set).List()[0].(map[string]interface{}); owner["email"] != "owner@example.com" {
+		t.Fatalf("the previous owner should be kept in the state, got %s", owner["email"])
+	}
+
+	state = p.refresh("gandi_domain", newState)
+	testCheckAttributes(t, state, map[string]string{
+		"owner_change.0.status":                  "pending",
+		"owner_change.0.pending":                 "true",
+		"owner_change.0.confirmation_required":   "true",
+		"owner_change.0.pending_confirmations.#": "2",
+		"owner_change.0.pending_confirmations.0": "new-owner@example.com",
+		"owner_change.0.pending_confirmations.1": "owner@example.com",
+	})
+
+	// The next apply waits for the pending owner change instead of
+	// submitting it again, until it is confirmed
+	api.ownerChangePolls = api.domains["example.com"].ownerChange.polls + 1
+	state = p.apply("gandi_domain", state, config)
+	testCheckAttributes(t, state, map[string]string{"owner_change.0.status": "done"})
+	if count := api.requestCount("POST domain/changeowner/example.com"); count != 1 {
+		t.Fatalf("expected the pending owner change to be resumed, got %d requests", count)
+	}
+}
+
+func TestOfflineDomain_registration(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	p := newTestOfflineProvider(t, api)
+
+	state := p.apply("gandi_domain", nil, testOfflineDomainConfig(map[string]interface{}{
+		"duration":  3,
+		"max_price": 40.0,
+		"currency":  "USD",
+	}))
+	testCheckAttributes(t, state, map[string]string{
+		"duration": "3",
+		"currency": "USD",
+	})
+	d := api.domains["example.com"].details
+	if years := d.Dates.RegistryEndsAt.Year() - d.Dates.CreatedAt.Year(); years != 3 {
+		t.Fatalf("expected the domain to be registered for 3 years, got %d", years)
+	}
+	// The availability is only checked before the registration
+	api.requests = nil
+	p.planEmpty("gandi_domain", state, testOfflineDomainConfig(map[string]interface{}{
+		"duration":  3,
+		"max_price": 40.0,
+		"currency":  "USD",
+	}))
+	if count := api.requestCount("GET domain/check"); count != 0 {
+		t.Fatalf("expected a registered domain not to be checked, got %d requests", count)
+	}
+	// Changing the order of a registered domain has no effect
+	p.planEmpty("gandi_domain", state, testOfflineDomainConfig(map[string]interface{}{
+		"duration":       5,
+		"max_price":      10.0,
+		"currency":       "EUR",
+		"accept_premium": true,
+	}))
+}
+
+func TestDomainCheckProduct_registrationPrice(t *testing.T) {
+	product := domainCheckProduct{Prices: []domainCheckPrice{
+		{MinDuration: 1, MaxDuration: 11, DurationUnit: "m", PriceBeforeTaxes: 2},
+		{MinDuration: 1, MaxDuration: 3, DurationUnit: "y", PriceBeforeTaxes: 20},
+		{MinDuration: 1, MaxDuration: 10, DurationUnit: "d", PriceBeforeTaxes: 1},
+	}}
+	for duration, expected := range map[int]float64{1: 20, 3: 60} {
+		if price, ok := product.registrationPrice(duration); !ok || price != expected {
+			t.Errorf("expected the price for %d years to be %v, got %v", duration, expected, price)
+		}
+	}
+	if _, ok := product.registrationPrice(5); ok {
+		t.Errorf("the prices in days should be ignored")
+	}
+
+	monthly := domainCheckProduct{Prices: []domainCheckPrice{
+		{MinDuration: 12, MaxDuration: 120, DurationUnit: "m", PriceBeforeTaxes: 1.5},
+	}}
+	if price, ok := monthly.registrationPrice(2); !ok || price != 36 {
+		t.Errorf("expected the monthly price to be converted, got %v", price)
+	}
+	if _, ok := (domainCheckProduct{Prices: []domainCheckPrice{{MinDuration: 1, MaxDuration: 10, PriceBeforeTaxes: 10}}}).registrationPrice(1); ok {
+		t.Errorf("the prices without a duration unit should be ignored")
+	}
+}
+
+// TestDomainOrder_checkMonthlyPrice checks max_price is compared with
+// the price of the whole registration, not the price of a month.
+func TestDomainOrder_checkMonthlyPrice(t *testing.T) {
+	product := domainCheckProduct{
+		Name:   "example.com",
+		Status: domainAvailable,
+		Prices: []domainCheckPrice{
+			{MinDuration: 12, MaxDuration: 120, DurationUnit: "m", PriceBeforeTaxes: 1.5},
+		},
+	}
+	if err := (domainOrder{duration: 1, maxPrice: 18}).check(product, "EUR"); err != nil {
+		t.Errorf("a registration at max_price should be allowed, got %s", err)
+	}
+	err := (domainOrder{duration: 1, maxPrice: 10}).check(product, "EUR")
+	if err == nil || !strings.Contains(err.Error(), "costs 18.00 EUR") {
+		t.Errorf("expected the yearly price to exceed max_price, got %v", err)
+	}
+	if err := (domainOrder{duration: 11, maxPrice: 1000}).check(product, "EUR"); err == nil {
+		t.Errorf("a duration beyond the prices should be rejected")
+	}
+}
+
+func TestOfflineDomain_registrationRejected(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addDomain("taken.com")
+	api.domainPrices["expensive.com"] = 500
+	api.premiumDomains["premium.com"] = true
+	api.domainPrices["premium.com"] = 100
+	p := newTestOfflineProvider(t, api)
+
+	for _, tc := range []struct {
+		name   string
+		config map[string]interface{}
+	}{
+		{"taken", map[string]interface{}{"name": "taken.com"}},
+		{"too expensive", map[string]interface{}{"name": "expensive.com", "max_price": 100.0}},
+		{"too expensive for the duration", map[string]interface{}{"duration": 10, "max_price": 100.0}},
+		{"premium", map[string]interface{}{"name": "premium.com"}},
+		{"invalid duration", map[string]interface{}{"duration": 11}},
+		{"invalid currency", map[string]interface{}{"currency": "euro"}},
+	} {
+		if _, diags := p.plan("gandi_domain", nil, testOfflineDomainConfig(tc.config)); !diags.HasError() {
+			t.Errorf("%s: expected the plan to fail", tc.name)
+		}
+	}
+	if len(api.domains) != 1 {
+		t.Fatalf("expected no domain to be registered, got %d domains", len(api.domains))
+	}
+
+	state := p.apply("gandi_domain", nil, testOfflineDomainConfig(map[string]interface{}{
+		"name":           "premium.com",
+		"accept_premium": true,
+		"max_price":      100.0,
+	}))
+	testCheckAttributes(t, state, map[string]string{"id": "premium.com"})
+}
+
+func TestOfflineDomain_transferLock(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	p := newTestOfflineProvider(t, api)
+
+	// Domains are locked by default
+	config := testOfflineDomainConfig(nil)
+	state := p.apply("gandi_domain", nil, config)
+	testCheckAttributes(t, state, map[string]string{
+		"transfer_lock": "true",
+		"status.#":      "1",
+		"status.0":      "clientTransferProhibited",
+	})
+	p.planEmpty("gandi_domain", state, config)
+
+	config["transfer_lock"] = false
+	state = p.apply("gandi_domain", state, config)
+	testCheckAttributes(t, state, map[string]string{
+		"transfer_lock": "false",
+		"status.#":      "0",
+	})
+
+	// The domain is locked again when it is unlocked outside Terraform
+	config["transfer_lock"] = true
+	state = p.apply("gandi_domain", state, config)
+	api.domains["example.com"].details.Status = []string{}
+	p = newTestOfflineProvider(t, api)
+	state = p.refresh("gandi_domain", state)
+	testCheckAttributes(t, state, map[string]string{"transfer_lock": "false"})
+	state = p.apply("gandi_domain", state, config)
+	testCheckAttributes(t, state, map[string]string{"transfer_lock": "true"})
+
+	// The lock is ignored with a warning for the TLDs which don't
+	// support it
+	api.addDomain("example.net")
+	api.domains["example.net"].details.CanTLDLock = Bool(false)
+	p = newTestOfflineProvider(t, api)
+	state = p.importState("gandi_domain", "example.net")
+	_, diags := p.applyWithDiags("gandi_domain", state, testOfflineDomainConfig(map[string]interface{}{"name": "example.net"}))
+	if diags.HasError() || len(diags) != 1 || diags[0].Severity != diag.Warning {
+		t.Fatalf("expected a warning for a TLD without transfer lock, got %v", diags)
+	}
+}
+
+func TestOfflineDomain_metadata(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addDomain("example.com")
+	details := &api.domains["example.com"].details
+	details.Status = []string{"clientTransferProhibited"}
+	details.SharingID = "00000000-0000-0000-0000-000000000042"
+	details.AuthInfo = "secret"
+	p := newTestOfflineProvider(t, api)
+
+	expected := map[string]string{
+		"tld":                 "com",
+		"sharing_id":          "00000000-0000-0000-0000-000000000042",
+		"status.#":            "1",
+		"status.0":            "clientTransferProhibited",
+		"authinfo_set":        "true",
+		"created_at":          details.Dates.CreatedAt.Format(time.RFC3339),
+		"registry_created_at": details.Dates.RegistryCreatedAt.Format(time.RFC3339),
+		"registry_ends_at":    details.Dates.RegistryEndsAt.Format(time.RFC3339),
+		"hold_begins_at":      "",
+	}
+	testCheckAttributes(t, p.importState("gandi_domain", "example.com"), expected)
+	testCheckAttributes(t, p.readDataSource("gandi_domain", map[string]interface{}{"name": "example.com"}), expected)
+}
+
+func TestOfflineDataDomain_basic(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addDomain("example.com")
+	p := newTestOfflineProvider(t, api)
+
+	state := p.readDataSource("gandi_domain", map[string]interface{}{"name": "example.com"})
+	testCheckAttributes(t, state, map[string]string{
+		"id":            "example.com",
+		"nameservers.#": "3",
+	})
+
+	if _, diags := p.readDataSourceWithDiags("gandi_domain", map[string]interface{}{"name": "unknown.com"}); !diags.HasError() {
+		t.Fatalf("reading an unknown domain should fail")
+	}
+}
@@ -0,0 +1,234 @@
+package gandi
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/go-gandi/go-gandi/domain"
+	"github.com/go-gandi/go-gandi/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceDomainTransfer() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDomainTransferCreate,
+		ReadContext:   resourceDomainTransferRead,
+		UpdateContext: resourceDomainTransferUpdate,
+		DeleteContext: resourceDomainTransferDelete,
+		Schema: map[string]*schema.Schema{
+			"fqdn": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The FQDN of the domain to transfer to Gandi",
+			},
+			"authinfo": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				Description: "The authorization code of the domain, given by its current registrar. Changing it updates the authorization code of the pending transfer.",
+			},
+			"owner":   transferContactSchema(false),
+			"admin":   transferContactSchema(true),
+			"billing": transferContactSchema(true),
+			"tech":    transferContactSchema(true),
+			"nameservers": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				ForceNew:    true,
+				Description: "A list of nameservers for the domain once transferred. LiveDNS is used by default.",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The current step of the transfer, 'done' once completed",
+			},
+			"step_number": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of the current step of the transfer",
+			},
+			"error": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The reason of the failure of the transfer, if any",
+			},
+			"foa_status": {
+				Type:        schema.TypeMap,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "The answers of the contacts to the form of authorization of the transfer, by email address",
+			},
+			"completed": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the domain has been transferred to Gandi. It can then be imported in a gandi_domain resource.",
+			},
+		},
+	}
+}
+
+// transferContactSchema returns the schema of a contact of the
+// transfer, which can't be changed once the transfer is started
+func transferContactSchema(optional bool) *schema.Schema {
+	s := contactSchema(optional)
+	s.ForceNew = true
+	return s
+}
+
+// domainTransferRequest starts the transfer of a domain, with the
+// domain/transferin endpoint which go-gandi doesn't cover
+type domainTransferRequest struct {
+	FQDN        string          `json:"fqdn"`
+	AuthInfo    string          `json:"authinfo"`
+	Owner       *domain.Contact `json:"owner"`
+	Admin       *domain.Contact `json:"admin,omitempty"`
+	Billing     *domain.Contact `json:"bill,omitempty"`
+	Tech        *domain.Contact `json:"tech,omitempty"`
+	Nameservers []string        `json:"nameservers,omitempty"`
+}
+
+// domainTransfer is the status of a transfer
+type domainTransfer struct {
+	FQDN           string              `json:"fqdn"`
+	Step           string              `json:"step"`
+	StepNumber     int                 `json:"step_number"`
+	ErrorType      string              `json:"errortype,omitempty"`
+	ErrorTypeLabel string              `json:"errortype_label,omitempty"`
+	FOA            []domainTransferFOA `json:"foa,omitempty"`
+}
+
+type domainTransferFOA struct {
+	Email  string `json:"email"`
+	Answer string `json:"answer"`
+}
+
+const domainTransferDone = "done"
+
+// domainTransferAPIFields maps the fields of the Gandi API errors to
+// the attributes of the transfer, when their names differ
+var domainTransferAPIFields = map[string]string{
+	"bill":       "billing",
+	"given":      "given_name",
+	"family":     "family_name",
+	"streetaddr": "street_addr",
+	"orgname":    "organisation",
+	"extra":      "extra_parameters",
+}
+
+func resourceDomainTransferCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*clients).API
+
+	fqdn := d.Get("fqdn").(string)
+	request := domainTransferRequest{
+		FQDN:     fqdn,
+		AuthInfo: d.Get("authinfo").(string),
+		Owner:    expandContact(d.Get("owner")),
+	}
+	if admin, ok := d.GetOk("admin"); ok {
+		request.Admin = expandContact(admin)
+	}
+	if billing, ok := d.GetOk("billing"); ok {
+		request.Billing = expandContact(billing)
+	}
+	if tech, ok := d.GetOk("tech"); ok {
+		request.Tech = expandContact(tech)
+	}
+	if nameservers, ok := d.GetOk("nameservers"); ok {
+		request.Nameservers = expandArray(nameservers.([]interface{}))
+	}
+
+	meta.(*clients).Cache.invalidateDomain(fqdn)
+	if err := client.Post(ctx, "domain/transferin", request, nil); err != nil {
+		return diagFromRequestError(fmt.Errorf("failed to start the transfer of %s: %w", fqdn, err), resourceDomainTransfer().Schema, domainTransferAPIFields)
+	}
+	d.SetId(fqdn)
+	return resourceDomainTransferRead(ctx, d, meta)
+}
+
+func resourceDomainTransferRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*clients).API
+	fqdn := d.Id()
+
+	var transfer domainTransfer
+	if err := client.Get(ctx, "domain/transferin/"+fqdn, &transfer); err != nil {
+		requestError, ok := err.(*types.RequestError)
+		if !ok || requestError.StatusCode != 404 {
+			return diag.FromErr(fmt.Errorf("failed to get the transfer of %s: %w", fqdn, err))
+		}
+		// The transfer is no longer listed once completed: the
+		// domain is then in the account
+		_, err = meta.(*clients).Cache.domainDetails(ctx, fqdn)
+		if requestErrorStatus(err) == 404 {
+			log.Printf("[WARN] the transfer of %s could not be found, removing it from the state", fqdn)
+			d.SetId("")
+			return nil
+		}
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("failed to get the domain %s: %w", fqdn, err))
+		}
+		transfer = domainTransfer{FQDN: fqdn, Step: domainTransferDone}
+	}
+
+	if err := d.Set("fqdn", fqdn); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set fqdn for %s: %w", d.Id(), err))
+	}
+	if err := d.Set("status", transfer.Step); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set status for %s: %w", d.Id(), err))
+	}
+	if err := d.Set("step_number", transfer.StepNumber); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set step_number for %s: %w", d.Id(), err))
+	}
+	transferError := transfer.ErrorTypeLabel
+	if transferError == "" {
+		transferError = transfer.ErrorType
+	}
+	if err := d.Set("error", transferError); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set error for %s: %w", d.Id(), err))
+	}
+	foaStatus := map[string]interface{}{}
+	for _, foa := range transfer.FOA {
+		foaStatus[foa.Email] = foa.Answer
+	}
+	if err := d.Set("foa_status", foaStatus); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set foa_status for %s: %w", d.Id(), err))
+	}
+	if err := d.Set("completed", transfer.Step == domainTransferDone); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set completed for %s: %w", d.Id(), err))
+	}
+	return nil
+}
+
+func resourceDomainTransferUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*clients).API
+	fqdn := d.Id()
+
+	if d.HasChange("authinfo") && !d.Get("completed").(bool) {
+		request := struct {
+			AuthInfo string `json:"authinfo"`
+		}{AuthInfo: d.Get("authinfo").(string)}
+		if err := client.Post(ctx, "domain/transferin/"+fqdn+"/authinfo", request, nil); err != nil {
+			return diagFromRequestError(fmt.Errorf("failed to update the authinfo of the transfer of %s: %w", fqdn, err), resourceDomainTransfer().Schema, domainTransferAPIFields)
+		}
+	}
+	return resourceDomainTransferRead(ctx, d, meta)
+}
+
+// The transfer is only removed from the state: the Gandi API doesn't
+// allow to cancel it, and a transferred domain is then managed by a
+// gandi_domain resource.
+func resourceDomainTransferDelete(ctx context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if !d.Get("completed").(bool) {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("The transfer of %s is still in progress", d.Id()),
+			Detail:   "The transfer has been removed from the state but it is not canceled.",
+		})
+	}
+	d.SetId("")
+	return diags
+}
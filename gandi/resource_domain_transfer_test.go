@@ -0,0 +1,111 @@
+package gandi
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+)
+
+func testOfflineDomainTransferConfig(extra map[string]interface{}) map[string]interface{} {
+	config := map[string]interface{}{
+		"fqdn":     "example.com",
+		"authinfo": "secret",
+		"owner":    testOfflineContact("owner@example.com"),
+	}
+	for k, v := range extra {
+		config[k] = v
+	}
+	return config
+}
+
+func TestOfflineDomainTransfer_basic(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	p := newTestOfflineProvider(t, api)
+
+	config := testOfflineDomainTransferConfig(map[string]interface{}{
+		"nameservers": []interface{}{"ns1.example.net", "ns2.example.net"},
+	})
+	state := p.apply("gandi_domain_transfer", nil, config)
+	testCheckAttributes(t, state, map[string]string{
+		"id":                           "example.com",
+		"status":                       "waiting_foa",
+		"step_number":                  "2",
+		"completed":                    "false",
+		"foa_status.%":                 "1",
+		"foa_status.owner@example.com": "pending",
+	})
+	if authinfo := api.transferRequests["example.com"].AuthInfo; authinfo != "secret" {
+		t.Fatalf("expected the authinfo to be sent, got %q", authinfo)
+	}
+
+	// A new authinfo relaunches the transfer
+	config["authinfo"] = "new-secret"
+	state = p.apply("gandi_domain_transfer", state, config)
+	if authinfo := api.transferRequests["example.com"].AuthInfo; authinfo != "new-secret" {
+		t.Fatalf("expected the authinfo to be updated, got %q", authinfo)
+	}
+	p.planEmpty("gandi_domain_transfer", state, config)
+
+	api.completeTransfer("example.com")
+	state = p.refresh("gandi_domain_transfer", state)
+	testCheckAttributes(t, state, map[string]string{
+		"status":                       "done",
+		"completed":                    "true",
+		"foa_status.owner@example.com": "approved",
+	})
+
+	// The transferred domain is then managed by a gandi_domain
+	domainState := p.importState("gandi_domain", "example.com")
+	testCheckAttributes(t, domainState, map[string]string{
+		"name":          "example.com",
+		"nameservers.#": "2",
+		"on_destroy":    "abandon",
+	})
+
+	// The transfer is only removed from the state once the domain is
+	// known to be missing, not on any failure to get it
+	delete(api.transfers, "example.com")
+	api.beforeRequest = func(r *http.Request) {
+		if r.URL.Path == "/v5/domain/domains/example.com" {
+			r.Header.Del("Authorization")
+		}
+	}
+	if _, diags := p.refreshWithDiags("gandi_domain_transfer", state); !diags.HasError() {
+		t.Fatalf("the failure to get the domain should be reported")
+	}
+	api.beforeRequest = nil
+	if refreshed := p.refresh("gandi_domain_transfer", state); refreshed == nil || refreshed.ID != "example.com" {
+		t.Fatalf("the completed transfer should be kept, got %v", refreshed)
+	}
+	delete(api.domains, "example.com")
+	if refreshed := p.refresh("gandi_domain_transfer", state); refreshed != nil && refreshed.ID != "" {
+		t.Fatalf("the transfer of a missing domain should be removed, got %v", refreshed)
+	}
+	api.addDomain("example.com")
+
+	p.destroy("gandi_domain_transfer", state)
+	if _, ok := api.domains["example.com"]; !ok {
+		t.Fatalf("removing the transfer should not remove the domain")
+	}
+}
+
+func TestOfflineDomainTransfer_errors(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addDomain("example.com")
+	p := newTestOfflineProvider(t, api)
+
+	if _, diags := p.applyWithDiags("gandi_domain_transfer", nil, testOfflineDomainTransferConfig(nil)); !diags.HasError() {
+		t.Fatalf("transferring a domain of the account should fail")
+	}
+	owner := testOfflineContact("owner@example.com")
+	owner[0].(map[string]interface{})["phone"] = "0606060606"
+	_, diags := p.applyWithDiags("gandi_domain_transfer", nil, testOfflineDomainTransferConfig(map[string]interface{}{
+		"fqdn":  "example.net",
+		"owner": owner,
+	}))
+	if len(diags) != 1 || !diags[0].AttributePath.Equals(cty.GetAttrPath("owner")) || !strings.Contains(diags[0].Summary, "owner.phone") {
+		t.Fatalf("expected an error about the phone attached to the owner, got %v", diags)
+	}
+}
@@ -1,11 +1,13 @@
 package gandi
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strings"
 
 	gandiemail "github.com/go-gandi/go-gandi/email"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -23,13 +25,18 @@ func resourceEmailForwarding() *schema.Resource {
 				Required:    true,
 				Description: "Forwards to email addresses",
 			},
+			"href": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The href of the forwarding",
+			},
 		},
-		Create: resourceEmailForwardingCreate,
-		Delete: resourceEmailForwardingDelete,
-		Read:   resourceEmailForwardingRead,
-		Update: resourceEmailForwardingUpdate,
+		CreateContext: resourceEmailForwardingCreate,
+		DeleteContext: resourceEmailForwardingDelete,
+		ReadContext:   resourceEmailForwardingRead,
+		UpdateContext: resourceEmailForwardingUpdate,
 		Importer: &schema.ResourceImporter{
-			State: resourceEmailForwardingImport,
+			StateContext: resourceEmailForwardingImport,
 		},
 	}
 }
@@ -39,8 +46,8 @@ func splitID(id string) (source, domain string) {
 	return parts[0], parts[1]
 }
 
-func resourceEmailForwardingCreate(d *schema.ResourceData, meta interface{}) (err error) {
-	client := meta.(*clients).Email
+func resourceEmailForwardingCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
 	source := d.Get("source").(string)
 	email, domain := splitID(source)
 
@@ -55,22 +62,26 @@ func resourceEmailForwardingCreate(d *schema.ResourceData, meta interface{}) (er
 		Destinations: destinations,
 	}
 
-	if err = client.CreateForward(domain, request); err != nil {
-		return
+	if err := c.callNonIdempotent(ctx, func() error { return c.Email.CreateForward(domain, request) }); err != nil {
+		return diagFromRequestError(err, resourceEmailForwarding().Schema, nil)
 	}
 
 	d.SetId(email + "@" + domain)
 
-	return resourceEmailForwardingRead(d, meta)
+	return resourceEmailForwardingRead(ctx, d, meta)
 }
 
-func resourceEmailForwardingRead(d *schema.ResourceData, meta interface{}) (err error) {
-	client := meta.(*clients).Email
+func resourceEmailForwardingRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
 	source, domain := splitID(d.Id())
 
-	forwards, err := client.GetForwards(domain)
-	if err != nil {
+	var forwards []gandiemail.GetForwardRequest
+	err := c.call(ctx, func() (err error) {
+		forwards, err = c.Email.GetForwards(domain)
 		return
+	})
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	var response gandiemail.GetForwardRequest
@@ -83,16 +94,19 @@ func resourceEmailForwardingRead(d *schema.ResourceData, meta interface{}) (err
 	}
 
 	if err = d.Set("source", d.Id()); err != nil {
-		return fmt.Errorf("failed to set source for %s: %s", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set source for %s: %s", d.Id(), err))
 	}
 	if err = d.Set("destinations", response.Destinations); err != nil {
-		return fmt.Errorf("failed to set destination for %s: %s", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set destination for %s: %s", d.Id(), err))
 	}
-	return
+	if err = d.Set("href", response.Href); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set href for %s: %s", d.Id(), err))
+	}
+	return nil
 }
 
-func resourceEmailForwardingUpdate(d *schema.ResourceData, meta interface{}) (err error) {
-	client := meta.(*clients).Email
+func resourceEmailForwardingUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
 	source, domain := splitID(d.Id())
 
 	var destinations []string
@@ -104,28 +118,32 @@ func resourceEmailForwardingUpdate(d *schema.ResourceData, meta interface{}) (er
 		Destinations: destinations,
 	}
 
-	err = client.UpdateForward(domain, source, request)
+	err := c.call(ctx, func() error { return c.Email.UpdateForward(domain, source, request) })
 	if err != nil {
-		return
+		return diagFromRequestError(err, resourceEmailForwarding().Schema, nil)
 	}
-	return resourceEmailForwardingRead(d, meta)
+	return resourceEmailForwardingRead(ctx, d, meta)
 }
 
-func resourceEmailForwardingDelete(d *schema.ResourceData, meta interface{}) (err error) {
-	client := meta.(*clients).Email
+func resourceEmailForwardingDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
 	source, domain := splitID(d.Id())
 
-	if err = client.DeleteForward(domain, source); err != nil {
-		return
+	if err := c.call(ctx, func() error { return c.Email.DeleteForward(domain, source) }); err != nil {
+		return diag.FromErr(err)
 	}
-	return
+	return nil
 }
 
-func resourceEmailForwardingImport(d *schema.ResourceData, meta interface{}) (data []*schema.ResourceData, err error) {
-	client := meta.(*clients).Email
+func resourceEmailForwardingImport(ctx context.Context, d *schema.ResourceData, meta interface{}) (data []*schema.ResourceData, err error) {
+	c := meta.(*clients)
 	source, domain := splitID(d.Id())
 
-	forwards, err := client.GetForwards(domain)
+	var forwards []gandiemail.GetForwardRequest
+	err = c.call(ctx, func() (err error) {
+		forwards, err = c.Email.GetForwards(domain)
+		return
+	})
 	if err != nil {
 		return
 	}
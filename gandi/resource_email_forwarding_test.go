@@ -0,0 +1,39 @@
+package gandi
+
+import (
+	"testing"
+)
+
+func TestOfflineEmailForwarding_basic(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	p := newTestOfflineProvider(t, api)
+	config := map[string]interface{}{
+		"source":       "contact@example.com",
+		"destinations": []interface{}{"john@example.org"},
+	}
+
+	state := p.apply("gandi_email_forwarding", nil, config)
+	testCheckAttributes(t, state, map[string]string{
+		"id":             "contact@example.com",
+		"destinations.#": "1",
+	})
+	p.planEmpty("gandi_email_forwarding", state, config)
+
+	config["destinations"] = []interface{}{"jane@example.org", "john@example.org"}
+	state = p.apply("gandi_email_forwarding", state, config)
+	if destinations := api.forwards["example.com"]["contact"].Destinations; len(destinations) != 2 {
+		t.Fatalf("the forwarding destinations should have been updated, got %v", destinations)
+	}
+
+	imported := p.importState("gandi_email_forwarding", "contact@example.com")
+	testCheckAttributes(t, imported, map[string]string{
+		"source":         "contact@example.com",
+		"destinations.#": "2",
+		"destinations.0": "jane@example.org",
+	})
+
+	p.destroy("gandi_email_forwarding", state)
+	if len(api.forwards["example.com"]) != 0 {
+		t.Fatalf("the forwarding should have been deleted")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/go-gandi/go-gandi/domain"
@@ -15,11 +16,11 @@ import (
 func resourceGlueRecord() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceGlueRecordCreate,
-		Read:          resourceGlueRecordRead,
+		ReadContext:   resourceGlueRecordRead,
 		UpdateContext: resourceGlueRecordUpdate,
 		DeleteContext: resourceGlueRecordDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceGlueRecordImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -62,7 +63,7 @@ func resourceGlueRecord() *schema.Resource {
 }
 
 func resourceGlueRecordCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*clients).Domain
+	c := meta.(*clients)
 	resDomain := d.Get("zone").(string)
 	name := d.Get("name").(string)
 
@@ -77,75 +78,82 @@ func resourceGlueRecordCreate(ctx context.Context, d *schema.ResourceData, meta
 		IPs:  ips,
 	}
 
-	err := client.CreateGlueRecord(resDomain, request)
+	err := c.callNonIdempotent(ctx, func() error { return c.Domain.CreateGlueRecord(resDomain, request) })
 	if err != nil {
-		return diag.Errorf("error creating instance: %s", err)
+		return diagFromRequestError(fmt.Errorf("error creating instance: %w", err), resourceGlueRecord().Schema, nil)
 	}
 
 	d.SetId(name)
 
-	return diag.FromErr(resource.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-		return resourceGlueRecordReadWithRetry(d, meta)
-	}))
+	return resourceGlueRecordReadWithRetry(ctx, d, meta)
 }
 
-func resourceGlueRecordRead(d *schema.ResourceData, meta interface{}) (err error) {
-	client := meta.(*clients).Domain
+func resourceGlueRecordRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
 	resDomain := d.Get("zone").(string)
 
 	id := d.Id()
 	var found domain.GlueRecord
-	found, err = client.GetGlueRecord(resDomain, id)
-	if err != nil {
+	err := c.call(ctx, func() (err error) {
+		found, err = c.Domain.GetGlueRecord(resDomain, id)
 		return
+	})
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	if found.Name == "" {
-		err = fmt.Errorf("cannot find Glue Record %s for zone %s", id, resDomain)
-		return
+		return diag.Errorf("cannot find Glue Record %s for zone %s", id, resDomain)
 	}
 
 	if err = d.Set("name", found.Name); err != nil {
-		return fmt.Errorf("failed to set name for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set name for %s: %w", d.Id(), err))
 	}
 	if err = d.Set("href", found.Href); err != nil {
-		return fmt.Errorf("failed to set href for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set href for %s: %w", d.Id(), err))
 	}
 	if err = d.Set("ips", found.IPs); err != nil {
-		return fmt.Errorf("failed to set ips for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set ips for %s: %w", d.Id(), err))
 	}
 	if err = d.Set("fqdn", found.FQDN); err != nil {
-		return fmt.Errorf("failed to set fqdn for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set fqdn for %s: %w", d.Id(), err))
 	}
 	if err = d.Set("fqdn_unicode", found.FQDNUnicode); err != nil {
-		return fmt.Errorf("failed to set fqdn unicode for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set fqdn unicode for %s: %w", d.Id(), err))
 	}
-	return
+	return nil
 }
 
-func resourceGlueRecordReadWithRetry(d *schema.ResourceData, meta interface{}) *resource.RetryError {
-	client := meta.(*clients).Domain
+// resourceGlueRecordReadWithRetry waits for the glue record to be
+// visible before reading it.
+func resourceGlueRecordReadWithRetry(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
 	resDomain := d.Get("zone").(string)
 	id := d.Id()
 
-	gluerecord, err := client.GetGlueRecord(resDomain, id)
-	if err != nil {
-		return resource.NonRetryableError(fmt.Errorf("error describing instance: %s", err))
-	}
-
-	if gluerecord.Name == "" {
-		return resource.RetryableError(fmt.Errorf("expected glue record to be created but was not found"))
-	}
+	err := resource.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		var gluerecord domain.GlueRecord
+		err := c.call(ctx, func() (err error) {
+			gluerecord, err = c.Domain.GetGlueRecord(resDomain, id)
+			return
+		})
+		if err != nil {
+			return resource.NonRetryableError(fmt.Errorf("error describing instance: %s", err))
+		}
 
-	err = resourceGlueRecordRead(d, meta)
+		if gluerecord.Name == "" {
+			return resource.RetryableError(fmt.Errorf("expected glue record to be created but was not found"))
+		}
+		return nil
+	})
 	if err != nil {
-		return resource.NonRetryableError(err)
+		return diag.FromErr(err)
 	}
-	return nil
+	return resourceGlueRecordRead(ctx, d, meta)
 }
 
 func resourceGlueRecordUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*clients).Domain
+	c := meta.(*clients)
 	resDomain := d.Get("zone").(string)
 	id := d.Id()
 
@@ -156,20 +164,32 @@ func resourceGlueRecordUpdate(ctx context.Context, d *schema.ResourceData, meta
 		}
 		sort.Strings(ips)
 
-		if err := client.UpdateGlueRecord(resDomain, id, ips); err != nil {
-			return diag.FromErr(fmt.Errorf("failed to update ips for glue record at %s: %w", id, err))
+		if err := c.call(ctx, func() error { return c.Domain.UpdateGlueRecord(resDomain, id, ips) }); err != nil {
+			return diagFromRequestError(fmt.Errorf("failed to update ips for glue record at %s: %w", id, err), resourceGlueRecord().Schema, nil)
 		}
 	}
-	return diag.FromErr(resource.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-		return resourceGlueRecordReadWithRetry(d, meta)
-	}))
+	return resourceGlueRecordReadWithRetry(ctx, d, meta)
 }
 
 func resourceGlueRecordDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*clients).Domain
+	c := meta.(*clients)
 	resDomain := d.Get("zone").(string)
 
 	id := d.Id()
 
-	return diag.FromErr(client.DeleteGlueRecord(resDomain, id))
+	return diag.FromErr(c.call(ctx, func() error { return c.Domain.DeleteGlueRecord(resDomain, id) }))
+}
+
+// resourceGlueRecordImport imports a glue record from a '{zone}/{name}'
+// ID since the zone is required to get the glue record.
+func resourceGlueRecordImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), "/", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("id format should be '{zone}/{name}'")
+	}
+	if err := d.Set("zone", parts[0]); err != nil {
+		return nil, fmt.Errorf("failed to set zone for %s: %w", d.Id(), err)
+	}
+	d.SetId(parts[1])
+	return []*schema.ResourceData{d}, nil
 }
@@ -0,0 +1,53 @@
+package gandi
+
+import (
+	"testing"
+)
+
+func TestOfflineGlueRecord_basic(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addDomain("example.com")
+	p := newTestOfflineProvider(t, api)
+	config := map[string]interface{}{
+		"zone": "example.com",
+		"name": "ns1",
+		"ips":  []interface{}{"192.168.0.1"},
+	}
+
+	state := p.apply("gandi_glue_record", nil, config)
+	testCheckAttributes(t, state, map[string]string{
+		"id":    "ns1",
+		"fqdn":  "ns1.example.com",
+		"ips.#": "1",
+	})
+	p.planEmpty("gandi_glue_record", state, config)
+
+	config["ips"] = []interface{}{"192.168.0.1", "192.168.0.2"}
+	state = p.apply("gandi_glue_record", state, config)
+	testCheckAttributes(t, state, map[string]string{
+		"ips.#": "2",
+		"ips.1": "192.168.0.2",
+	})
+
+	data := p.readDataSource("gandi_glue_record", map[string]interface{}{
+		"zone": "example.com",
+		"name": "ns1",
+	})
+	testCheckAttributes(t, data, map[string]string{
+		"id":    "ns1",
+		"ips.#": "2",
+	})
+
+	imported := p.importState("gandi_glue_record", "example.com/ns1")
+	testCheckAttributes(t, imported, map[string]string{
+		"id":    "ns1",
+		"zone":  "example.com",
+		"name":  "ns1",
+		"ips.#": "2",
+	})
+
+	p.destroy("gandi_glue_record", state)
+	if _, ok := api.domains["example.com"].hosts["ns1"]; ok {
+		t.Fatalf("the glue record should have been deleted")
+	}
+}
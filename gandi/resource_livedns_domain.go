@@ -1,19 +1,21 @@
 package gandi
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	"github.com/go-gandi/go-gandi/livedns"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceLiveDNSDomain() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceLiveDNSDomainCreate,
-		Read:   resourceLiveDNSDomainRead,
-		Update: resourceLiveDNSDomainUpdate,
-		Delete: resourceLiveDNSDomainDelete,
+		CreateContext: resourceLiveDNSDomainCreate,
+		ReadContext:   resourceLiveDNSDomainRead,
+		UpdateContext: resourceLiveDNSDomainUpdate,
+		DeleteContext: resourceLiveDNSDomainDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -43,56 +45,78 @@ func resourceLiveDNSDomain() *schema.Resource {
 	}
 }
 
-func resourceLiveDNSDomainCreate(d *schema.ResourceData, meta interface{}) error {
+// liveDNSDomainAPIFields maps the fields of the Gandi API errors to
+// the attributes of the LiveDNS domain, when their names differ
+var liveDNSDomainAPIFields = map[string]string{
+	"fqdn":     "name",
+	"zone.ttl": "ttl",
+}
+
+func resourceLiveDNSDomainCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	soaTTL := 86400
 	name := d.Get("name").(string)
 	if ttl, ok := d.GetOk("ttl"); ok {
 		soaTTL = ttl.(int)
 	}
-	client := meta.(*clients).LiveDNS
-	_, err := client.CreateDomain(name, soaTTL)
-	if err != nil {
+	c := meta.(*clients)
+	err := c.callNonIdempotent(ctx, func() error {
+		_, err := c.LiveDNS.CreateDomain(name, soaTTL)
 		return err
+	})
+	if err != nil {
+		return diagFromRequestError(err, resourceLiveDNSDomain().Schema, liveDNSDomainAPIFields)
 	}
 	d.SetId(name)
 	autosnap := d.Get("automatic_snapshots").(bool)
-	if _, err := client.UpdateDomain(name, livedns.UpdateDomainRequest{AutomaticSnapshots: &autosnap}); err != nil {
-		return fmt.Errorf("failed to enable automatic snapshots for %s: %w", name, err)
+	err = c.callNonIdempotent(ctx, func() error {
+		_, err := c.LiveDNS.UpdateDomain(name, livedns.UpdateDomainRequest{AutomaticSnapshots: &autosnap})
+		return err
+	})
+	if err != nil {
+		return diagFromRequestError(fmt.Errorf("failed to enable automatic snapshots for %s: %w", name, err), resourceLiveDNSDomain().Schema, liveDNSDomainAPIFields)
 	}
-	return resourceLiveDNSDomainRead(d, meta)
+	return resourceLiveDNSDomainRead(ctx, d, meta)
 }
 
-func resourceLiveDNSDomainRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*clients).LiveDNS
-	zone, err := client.GetDomain(d.Id())
+func resourceLiveDNSDomainRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
+	var zone livedns.Domain
+	err := c.call(ctx, func() (err error) {
+		zone, err = c.LiveDNS.GetDomain(d.Id())
+		return
+	})
 	if err != nil {
 		d.SetId("")
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(zone.FQDN)
 	if err = d.Set("name", zone.FQDN); err != nil {
-		return fmt.Errorf("failed to set name for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set name for %s: %w", d.Id(), err))
 	}
 	if err = d.Set("automatic_snapshots", zone.AutomaticSnapshots); err != nil {
-		return fmt.Errorf("failed to set automatic_snapshots for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set automatic_snapshots for %s: %w", d.Id(), err))
 	}
 	return nil
 }
 
-func resourceLiveDNSDomainUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*clients).LiveDNS
+func resourceLiveDNSDomainUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
 	name := d.Get("name").(string)
 
 	if d.HasChange("automatic_snapshots") {
 		a := d.Get("automatic_snapshots").(bool)
-		if _, err := client.UpdateDomain(name, livedns.UpdateDomainRequest{AutomaticSnapshots: &a}); err != nil {
-			return fmt.Errorf("failed to enable automatic snapshots for %s: %w", name, err)
+		err := c.callNonIdempotent(ctx, func() error {
+			_, err := c.LiveDNS.UpdateDomain(name, livedns.UpdateDomainRequest{AutomaticSnapshots: &a})
+			return err
+		})
+		if err != nil {
+			return diagFromRequestError(fmt.Errorf("failed to enable automatic snapshots for %s: %w", name, err), resourceLiveDNSDomain().Schema, liveDNSDomainAPIFields)
 		}
 	}
-	return resourceLiveDNSDomainRead(d, meta)
+	return resourceLiveDNSDomainRead(ctx, d, meta)
 }
 
-func resourceLiveDNSDomainDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceLiveDNSDomainDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	d.SetId("")
 	return nil
 }
@@ -0,0 +1,54 @@
+package gandi
+
+import (
+	"testing"
+)
+
+func TestOfflineLiveDNSDomain_basic(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	p := newTestOfflineProvider(t, api)
+	config := map[string]interface{}{
+		"name":                "example.com",
+		"automatic_snapshots": false,
+	}
+
+	state := p.apply("gandi_livedns_domain", nil, config)
+	testCheckAttributes(t, state, map[string]string{
+		"id":                  "example.com",
+		"automatic_snapshots": "false",
+	})
+	p.planEmpty("gandi_livedns_domain", state, config)
+
+	config["automatic_snapshots"] = true
+	state = p.apply("gandi_livedns_domain", state, config)
+	if !*api.zones["example.com"].domain.AutomaticSnapshots {
+		t.Fatalf("automatic snapshots should have been enabled")
+	}
+
+	imported := p.importState("gandi_livedns_domain", "example.com")
+	testCheckAttributes(t, imported, map[string]string{
+		"name":                "example.com",
+		"automatic_snapshots": "true",
+	})
+
+	p.destroy("gandi_livedns_domain", state)
+}
+
+func TestOfflineDataLiveDNSDomain_basic(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addZone("example.com")
+	p := newTestOfflineProvider(t, api)
+
+	state := p.readDataSource("gandi_livedns_domain", map[string]interface{}{"name": "example.com"})
+	testCheckAttributes(t, state, map[string]string{
+		"id":   "example.com",
+		"name": "example.com",
+	})
+
+	state = p.readDataSource("gandi_livedns_domain_ns", map[string]interface{}{"name": "example.com"})
+	testCheckAttributes(t, state, map[string]string{
+		"id":            "example.com",
+		"nameservers.#": "3",
+		"nameservers.0": "ns-1-a.gandi.net",
+	})
+}
@@ -1,12 +1,14 @@
 package gandi
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/go-gandi/go-gandi/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -14,13 +16,14 @@ const TXT = "TXT"
 
 func resourceLiveDNSRecord() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceLiveDNSRecordCreate,
-		Read:   resourceLiveDNSRecordRead,
-		Update: resourceLiveDNSRecordUpdate,
-		Delete: resourceLiveDNSRecordDelete,
+		CreateContext: resourceLiveDNSRecordCreate,
+		ReadContext:   resourceLiveDNSRecordRead,
+		UpdateContext: resourceLiveDNSRecordUpdate,
+		DeleteContext: resourceLiveDNSRecordDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		CustomizeDiff: resourceLiveDNSRecordCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
 			"zone": {
@@ -36,10 +39,12 @@ func resourceLiveDNSRecord() *schema.Resource {
 				Description: "The name of the record",
 			},
 			"type": {
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				Description: "The type of the record",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateRecordType,
+				StateFunc:    upperCaseRecordType,
+				Description:  "The type of the record",
 			},
 			"ttl": {
 				Type:        schema.TypeInt,
@@ -52,15 +57,22 @@ func resourceLiveDNSRecord() *schema.Resource {
 				Description: "The href of the record",
 			},
 			"values": {
-				Type:        schema.TypeSet,
-				Elem:        &schema.Schema{Type: schema.TypeString},
-				Required:    true,
-				Description: "A list of values of the record",
+				Type:             schema.TypeSet,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				Optional:         true,
+				Computed:         true,
+				ExactlyOneOf:     recordValuesKeys,
+				DiffSuppressFunc: suppressEquivalentRecordValues,
+				Description:      "A list of values of the record",
 			},
+			"mx":   recordBlocks["mx"].schema(),
+			"srv":  recordBlocks["srv"].schema(),
+			"caa":  recordBlocks["caa"].schema(),
+			"tlsa": recordBlocks["tlsa"].schema(),
 			"mutable": {
 				Type:        schema.TypeBool,
 				Optional:    true,
-				Description: "Define if the record can be modified outside Terraform (this currently only works for TXT records)",
+				Description: "Define if the record can be modified outside Terraform. Only the values of this resource are then managed, the other values of the record are kept. Each value is owned by a single resource: adding a value which is already in the record fails, so that destroying the resource only removes the values it added.",
 			},
 		},
 		Timeouts: &schema.ResourceTimeout{Default: schema.DefaultTimeout(1 * time.Minute)},
@@ -81,56 +93,47 @@ func expandRecordID(id string) (zone, name, recordType string, err error) {
 	return
 }
 
-func createRecord(d *schema.ResourceData, meta interface{}, zoneUUID, name, recordType string, ttl int, values []string) error {
-	client := meta.(*clients).LiveDNS
-
-	_, err := client.CreateDomainRecord(zoneUUID, name, recordType, ttl, values)
-	if err != nil {
-		return err
-	}
-	calculatedID := fmt.Sprintf("%s/%s/%s", zoneUUID, name, recordType)
-	d.SetId(calculatedID)
-	return resourceLiveDNSRecordRead(d, meta)
+// liveDNSRecordAPIFields maps the fields of the Gandi API errors to
+// the attributes of the record, when their names differ
+var liveDNSRecordAPIFields = map[string]string{
+	"rrset_name":   "name",
+	"rrset_type":   "type",
+	"rrset_ttl":    "ttl",
+	"rrset_values": "values",
 }
 
-func resourceLiveDNSRecordCreate(d *schema.ResourceData, meta interface{}) error {
-	zoneUUID := d.Get("zone").(string)
+func resourceLiveDNSRecordCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
+	zone := d.Get("zone").(string)
 	name := d.Get("name").(string)
-	recordType := d.Get("type").(string)
+	recordType := strings.ToUpper(d.Get("type").(string))
 	ttl := d.Get("ttl").(int)
-	valuesList := d.Get("values").(*schema.Set).List()
-	mutable := d.Get("mutable").(bool)
+	values := expandArray(d.Get("values").(*schema.Set).List())
 
-	var values []string
-	for _, v := range valuesList {
-		values = append(values, v.(string))
-	}
-	client := meta.(*clients).LiveDNS
-
-	// retrieve existing records - create if not exists otherwise update records with new values
-	if recordType == TXT && mutable {
-		rec, err := client.GetDomainRecordByNameAndType(zoneUUID, name, recordType)
-		if err != nil {
-			return createRecord(d, meta, zoneUUID, name, recordType, ttl, values)
-		}
-		values = append(values, rec.RrsetValues...)
-		_, err = client.UpdateDomainRecordByNameAndType(zoneUUID, name, recordType, ttl, values)
-		if err != nil {
-			return err
+	c.Cache.invalidateZone(zone)
+	if d.Get("mutable").(bool) {
+		// add the new values to the existing record, creating it if it doesn't exist
+		if err := updateMutableRecord(ctx, c, zone, name, recordType, ttl, nil, values); err != nil {
+			return diagFromRequestError(err, resourceLiveDNSRecord().Schema, liveDNSRecordAPIFields)
 		}
+	} else if err := c.callNonIdempotent(ctx, func() error {
+		_, err := c.LiveDNS.CreateDomainRecord(zone, name, recordType, ttl, splitTXTValues(recordType, values))
+		return err
+	}); err != nil {
+		return diagFromRequestError(err, resourceLiveDNSRecord().Schema, liveDNSRecordAPIFields)
 	}
-	return createRecord(d, meta, zoneUUID, name, recordType, ttl, values)
+	d.SetId(fmt.Sprintf("%s/%s/%s", zone, name, recordType))
+	return resourceLiveDNSRecordRead(ctx, d, meta)
 }
 
-func resourceLiveDNSRecordRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*clients).LiveDNS
+func resourceLiveDNSRecordRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	zone, name, recordType, err := expandRecordID(d.Id())
 	mutable := d.Get("mutable").(bool)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	record, err := client.GetDomainRecordByNameAndType(zone, name, recordType)
+	record, err := meta.(*clients).Cache.zoneRecord(ctx, zone, name, recordType)
 
 	if err != nil {
 		requestError, ok := err.(*types.RequestError)
@@ -138,132 +141,95 @@ func resourceLiveDNSRecordRead(d *schema.ResourceData, meta interface{}) error {
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diag.FromErr(err)
 	}
+	record.RrsetValues = joinTXTValues(recordType, record.RrsetValues)
 
 	if err = d.Set("zone", zone); err != nil {
-		return fmt.Errorf("failed to set zone for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set zone for %s: %w", d.Id(), err))
 	}
 	if err = d.Set("name", record.RrsetName); err != nil {
-		return fmt.Errorf("failed to set name for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set name for %s: %w", d.Id(), err))
 	}
 	if err = d.Set("type", record.RrsetType); err != nil {
-		return fmt.Errorf("failed to set type for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set type for %s: %w", d.Id(), err))
 	}
 	if err = d.Set("ttl", record.RrsetTTL); err != nil {
-		return fmt.Errorf("failed to set ttl for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set ttl for %s: %w", d.Id(), err))
 	}
 	if err = d.Set("href", record.RrsetHref); err != nil {
-		return fmt.Errorf("failed to set href for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set href for %s: %w", d.Id(), err))
 	}
-	if recordType == TXT && mutable {
+	if mutable {
 		// Keep only values that are both in terraform and in the api
-		valuesList := d.Get("values").(*schema.Set).List()
-		var tfValues []string
-		for _, v := range valuesList {
-			tfValues = append(tfValues, v.(string))
-		}
-
-		values := keepRecordsInApiAndTF(tfValues, record.RrsetValues)
+		tfValues := expandArray(d.Get("values").(*schema.Set).List())
+		values := keepOwnedRecordValues(zone, recordType, tfValues, record.RrsetValues)
 		if err = d.Set("values", values); err != nil {
-			return fmt.Errorf("failed to set the values for %s: %w", d.Id(), err)
+			return diag.FromErr(fmt.Errorf("failed to set the values for %s: %w", d.Id(), err))
 		}
 	} else {
 		if err = d.Set("values", record.RrsetValues); err != nil {
-			return fmt.Errorf("failed to set the values for %s: %w", d.Id(), err)
+			return diag.FromErr(fmt.Errorf("failed to set the values for %s: %w", d.Id(), err))
 		}
 	}
+	if err = setRecordBlocks(d, record.RrsetType, expandArray(d.Get("values").(*schema.Set).List())); err != nil {
+		return diag.FromErr(err)
+	}
 
 	return nil
 }
 
-func resourceLiveDNSRecordUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*clients).LiveDNS
+func resourceLiveDNSRecordUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
 	zone, name, recordType, err := expandRecordID(d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	mutable := d.Get("mutable").(bool)
 	ttl := d.Get("ttl").(int)
-	valuesList := d.Get("values").(*schema.Set).List()
-	var values []string
-	for _, v := range valuesList {
-		values = append(values, v.(string))
-	}
-
-	if recordType == TXT && mutable {
-		rec, err := client.GetDomainRecordByNameAndType(zone, name, recordType)
-		if err != nil {
-			return err
-		}
+	values := expandArray(d.Get("values").(*schema.Set).List())
 
-		// get current state records
+	c.Cache.invalidateZone(zone)
+	if d.Get("mutable").(bool) {
+		// replace the current state records by the new ones in the api records list
 		stateRecords, _ := d.GetChange("values")
-		var currentRecords []string
-		for _, v := range stateRecords.(*schema.Set).List() {
-			currentRecords = append(currentRecords, v.(string))
+		currentRecords := expandArray(stateRecords.(*schema.Set).List())
+		if err = updateMutableRecord(ctx, c, zone, name, recordType, ttl, currentRecords, values); err != nil {
+			return diagFromRequestError(err, resourceLiveDNSRecord().Schema, liveDNSRecordAPIFields)
 		}
-		// clean update by removing current state records from the api records list then add new records to the list
-		values = getUpdatedTXTRecordsList(currentRecords, rec.RrsetValues, values)
+		return resourceLiveDNSRecordRead(ctx, d, meta)
 	}
 
-	_, err = client.UpdateDomainRecordByNameAndType(zone, name, recordType, ttl, values)
-	if err != nil {
+	err = c.call(ctx, func() error {
+		_, err := c.LiveDNS.UpdateDomainRecordByNameAndType(zone, name, recordType, ttl, splitTXTValues(recordType, values))
 		return err
+	})
+	if err != nil {
+		return diagFromRequestError(err, resourceLiveDNSRecord().Schema, liveDNSRecordAPIFields)
 	}
-	return resourceLiveDNSRecordRead(d, meta)
+	return resourceLiveDNSRecordRead(ctx, d, meta)
 }
 
-func resourceLiveDNSRecordDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*clients).LiveDNS
+func resourceLiveDNSRecordDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
 	zone, name, recordType, err := expandRecordID(d.Id())
-	mutable := d.Get("mutable").(bool)
-
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	if recordType == TXT && mutable {
-		zoneUUID := d.Get("zone").(string)
-		valuesList := d.Get("values").(*schema.Set).List()
+	c.Cache.invalidateZone(zone)
+	if d.Get("mutable").(bool) {
+		// remove the values owned by terraform from the record, it is
+		// deleted if no other value remains
+		values := expandArray(d.Get("values").(*schema.Set).List())
 		ttl := d.Get("ttl").(int)
-
-		rec, err := client.GetDomainRecordByNameAndType(zoneUUID, name, recordType)
-		if err != nil {
-			return err
-		}
-
-		var values []string
-		for _, v := range valuesList {
-			values = append(values, v.(string))
-		}
-		apiValuesWrappedWithQuotes := wrapRecordsWithQuotes(rec.RrsetValues)
-		valuesListWrappedWithQuotes := wrapRecordsWithQuotes(values)
-
-		// if terraform and api return the same records list then we can safely remove records
-		// otherwise we need to remove terraform managed records from the records list and update it
-		if areStringSlicesEqual(apiValuesWrappedWithQuotes, valuesListWrappedWithQuotes) {
-			if err = client.DeleteDomainRecord(zone, name, recordType); err != nil {
-				return err
-			}
-		} else {
-			var values []string = apiValuesWrappedWithQuotes
-			for _, v := range valuesListWrappedWithQuotes {
-				index, exists := containsRecord(values, v)
-				if exists {
-					values = removeRecordFromValuesList(values, index)
-				}
-			}
-			_, err = client.UpdateDomainRecordByNameAndType(zoneUUID, name, recordType, ttl, values)
-			if err != nil {
-				return err
-			}
-		}
-	} else {
-		if err = client.DeleteDomainRecord(zone, name, recordType); err != nil {
-			return err
+		if err = updateMutableRecord(ctx, c, zone, name, recordType, ttl, values, nil); err != nil {
+			return diag.FromErr(err)
 		}
+	} else if err = c.call(ctx, func() error {
+		return c.LiveDNS.DeleteDomainRecord(zone, name, recordType)
+	}); err != nil {
+		return diag.FromErr(err)
 	}
 
 	d.SetId("")
@@ -0,0 +1,210 @@
+package gandi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// recordBlock describes a nested block of gandi_livedns_record holding
+// the values of a record type in a structured way.
+type recordBlock struct {
+	recordType string
+	// fields are the attributes of the block, in the order they
+	// appear in the record value
+	fields []string
+	// stringFields are the fields which are not numbers
+	stringFields map[string]bool
+	// quotedField is the field which is quoted in the record value
+	quotedField string
+}
+
+var recordBlocks = map[string]recordBlock{
+	"mx": {
+		recordType:   "MX",
+		fields:       []string{"priority", "host"},
+		stringFields: map[string]bool{"host": true},
+	},
+	"srv": {
+		recordType:   "SRV",
+		fields:       []string{"priority", "weight", "port", "target"},
+		stringFields: map[string]bool{"target": true},
+	},
+	"caa": {
+		recordType:   "CAA",
+		fields:       []string{"flags", "tag", "value"},
+		stringFields: map[string]bool{"tag": true, "value": true},
+		quotedField:  "value",
+	},
+	"tlsa": {
+		recordType:   "TLSA",
+		fields:       []string{"usage", "selector", "matching_type", "certificate_data"},
+		stringFields: map[string]bool{"certificate_data": true},
+	},
+}
+
+var recordBlockDescriptions = map[string]string{
+	"priority":         "The priority of the target host",
+	"host":             "The hostname of the mail server",
+	"weight":           "The weight of the target host among the ones with the same priority",
+	"port":             "The port of the service",
+	"target":           "The hostname of the target host",
+	"flags":            "The flags of the CAA record",
+	"tag":              "The property tag, such as issue, issuewild or iodef",
+	"value":            "The value of the property, without quotes",
+	"usage":            "The certificate usage",
+	"selector":         "The part of the certificate which is matched",
+	"matching_type":    "How the certificate data is matched",
+	"certificate_data": "The hexadecimal certificate association data",
+}
+
+func (b recordBlock) schema() *schema.Schema {
+	fields := make(map[string]*schema.Schema)
+	for _, f := range b.fields {
+		fieldType := schema.TypeInt
+		if b.stringFields[f] {
+			fieldType = schema.TypeString
+		}
+		fields[f] = &schema.Schema{
+			Type:        fieldType,
+			Required:    true,
+			Description: recordBlockDescriptions[f],
+		}
+	}
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Optional:    true,
+		Computed:    true,
+		Description: fmt.Sprintf("The values of a %s record, instead of values", b.recordType),
+		Elem:        &schema.Resource{Schema: fields},
+	}
+}
+
+// format serializes a block into a record value
+func (b recordBlock) format(block map[string]interface{}) string {
+	fields := make([]string, 0, len(b.fields))
+	for _, f := range b.fields {
+		switch v := block[f].(type) {
+		case int:
+			fields = append(fields, strconv.Itoa(v))
+		case string:
+			if f == b.quotedField {
+				v = strconv.Quote(v)
+			}
+			fields = append(fields, v)
+		}
+	}
+	return strings.Join(fields, " ")
+}
+
+// parse parses a record value into a block
+func (b recordBlock) parse(value string) (map[string]interface{}, error) {
+	fields := strings.SplitN(strings.Join(strings.Fields(value), " "), " ", len(b.fields))
+	if len(fields) != len(b.fields) {
+		return nil, fmt.Errorf("the %s value %q has not %d fields", b.recordType, value, len(b.fields))
+	}
+	block := make(map[string]interface{})
+	for i, f := range b.fields {
+		if !b.stringFields[f] {
+			n, err := strconv.Atoi(fields[i])
+			if err != nil {
+				return nil, fmt.Errorf("the %s of the %s value %q is not a number", f, b.recordType, value)
+			}
+			block[f] = n
+			continue
+		}
+		if f == b.quotedField && isRecordWrappedWithQuotes(fields[i]) {
+			unquoted, err := strconv.Unquote(fields[i])
+			if err != nil {
+				unquoted = fields[i][1 : len(fields[i])-1]
+			}
+			fields[i] = unquoted
+		}
+		block[f] = fields[i]
+	}
+	return block, nil
+}
+
+func (b recordBlock) formatAll(blocks []interface{}) []string {
+	values := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		values = append(values, b.format(block.(map[string]interface{})))
+	}
+	return values
+}
+
+func (b recordBlock) parseAll(values []string) ([]interface{}, error) {
+	blocks := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		block, err := b.parse(v)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+// recordValuesKeys are the attributes which can hold the values of a
+// record, only one of them can be set
+var recordValuesKeys = []string{"values", "mx", "srv", "caa", "tlsa"}
+
+// setRecordBlocks sets the block matching the type of the record from
+// its values, and empties the other blocks.
+func setRecordBlocks(d *schema.ResourceData, recordType string, values []string) error {
+	for key, b := range recordBlocks {
+		blocks := []interface{}{}
+		if b.recordType == recordType {
+			if parsed, err := b.parseAll(values); err == nil {
+				blocks = parsed
+			}
+		}
+		if err := d.Set(key, blocks); err != nil {
+			return fmt.Errorf("failed to set %s for %s: %w", key, d.Id(), err)
+		}
+	}
+	return nil
+}
+
+// customizeRecordBlocksDiff keeps the values and the blocks of a record
+// consistent: the values are computed from the blocks when they are
+// changed and the block matching the type from the values otherwise.
+func customizeRecordBlocksDiff(d *schema.ResourceDiff) error {
+	recordType := strings.ToUpper(d.Get("type").(string))
+	for key, b := range recordBlocks {
+		if !d.HasChange(key) {
+			continue
+		}
+		if !d.NewValueKnown(key) {
+			return d.SetNewComputed("values")
+		}
+		blocks := d.Get(key).(*schema.Set).List()
+		if len(blocks) == 0 {
+			continue
+		}
+		if recordType != b.recordType {
+			return fmt.Errorf("%s blocks can only be used with %s records", key, b.recordType)
+		}
+		return d.SetNew("values", b.formatAll(blocks))
+	}
+
+	if !d.HasChange("values") {
+		return nil
+	}
+	for key, b := range recordBlocks {
+		if b.recordType != recordType {
+			continue
+		}
+		if !d.NewValueKnown("values") {
+			return d.SetNewComputed(key)
+		}
+		blocks, err := b.parseAll(expandArray(d.Get("values").(*schema.Set).List()))
+		if err != nil {
+			return d.SetNewComputed(key)
+		}
+		return d.SetNew(key, blocks)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package gandi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-gandi/go-gandi/livedns"
+	"github.com/go-gandi/go-gandi/types"
+)
+
+// mutableRecordMaxAttempts is the number of times the update of a
+// mutable record is attempted when another writer modifies it
+// concurrently
+const mutableRecordMaxAttempts = 5
+
+// mutableRecordRetryDelay is the delay before the second attempt, it
+// grows linearly with the attempts
+var mutableRecordRetryDelay = 500 * time.Millisecond
+
+// getRecordValues returns the values of a record, joined for TXT
+// records, and whether the record exists.
+func getRecordValues(ctx context.Context, c *clients, zone, name, recordType string) ([]string, bool, error) {
+	var record livedns.DomainRecord
+	err := c.call(ctx, func() (err error) {
+		record, err = c.LiveDNS.GetDomainRecordByNameAndType(zone, name, recordType)
+		return
+	})
+	if err != nil {
+		requestError, ok := err.(*types.RequestError)
+		if ok && requestError.StatusCode == 404 {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return joinTXTValues(recordType, record.RrsetValues), true, nil
+}
+
+// missingOtherValues returns the values of the record before it was
+// written, owned by other writers, which are missing from its values.
+func missingOtherValues(zone, recordType string, values, before, ownedValues []string) []string {
+	current := canonicalRecordValues(zone, recordType, values)
+	owned := canonicalRecordValues(zone, recordType, ownedValues)
+	var missing []string
+	for _, v := range canonicalRecordValues(zone, recordType, before) {
+		_, isOwned := containsRecord(owned, v)
+		if _, exists := containsRecord(current, v); !exists && !isOwned {
+			missing = append(missing, v)
+		}
+	}
+	return missing
+}
+
+// alreadyPresentValues returns the new values which are not owned yet
+// but already in the record: they belong to another writer, so that
+// adding them would share their ownership.
+func alreadyPresentValues(zone, recordType string, values, ownedValues, newValues []string) []string {
+	current := canonicalRecordValues(zone, recordType, values)
+	owned := canonicalRecordValues(zone, recordType, ownedValues)
+	var present []string
+	for _, v := range canonicalRecordValues(zone, recordType, newValues) {
+		_, isOwned := containsRecord(owned, v)
+		if _, exists := containsRecord(current, v); exists && !isOwned {
+			present = append(present, v)
+		}
+	}
+	return present
+}
+
+// ownedValuesApplied returns true if the values of a record contain all
+// the new values owned by Terraform and none of the ones it no longer
+// owns.
+func ownedValuesApplied(zone, recordType string, values, ownedValues, newValues []string) bool {
+	current := canonicalRecordValues(zone, recordType, values)
+	wanted := canonicalRecordValues(zone, recordType, newValues)
+	for _, v := range wanted {
+		if _, exists := containsRecord(current, v); !exists {
+			return false
+		}
+	}
+	for _, v := range canonicalRecordValues(zone, recordType, ownedValues) {
+		_, isWanted := containsRecord(wanted, v)
+		if _, exists := containsRecord(current, v); exists && !isWanted {
+			return false
+		}
+	}
+	return true
+}
+
+// updateMutableRecord replaces the values owned by Terraform in a
+// mutable record by the new ones, keeping the values of the other
+// writers. The record is created when it doesn't exist yet and deleted
+// when no value remains. Each value has a single owner: a new value
+// already in the record is rejected, since removing it later would
+// remove it for its other writer.
+//
+// Since LiveDNS doesn't support conditional requests, conflicts with
+// concurrent writers are detected once the record is written: it must
+// contain the new owned values, none of the ones no longer owned, and
+// all the values of the other writers it contained before. On
+// conflict, the update is attempted again from the new values, unless
+// the context is done.
+func updateMutableRecord(ctx context.Context, c *clients, zone, name, recordType string, ttl int, ownedValues, newValues []string) error {
+	var conflict string
+	for attempt := 1; attempt <= mutableRecordMaxAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("failed to update the record %s %s of zone %s: %w, the last conflict is that %s",
+					name, recordType, zone, ctx.Err(), conflict)
+			case <-time.After(time.Duration(attempt-1) * mutableRecordRetryDelay):
+			}
+		}
+
+		current, exists, err := getRecordValues(ctx, c, zone, name, recordType)
+		if err != nil {
+			return err
+		}
+		// The values written by a previous attempt are owned
+		if attempt == 1 {
+			if present := alreadyPresentValues(zone, recordType, current, ownedValues, newValues); len(present) > 0 {
+				return fmt.Errorf("the values %v of the record %s %s of zone %s are already present: they are owned by another resource or were added outside Terraform, remove them from the record to manage them with this resource",
+					present, name, recordType, zone)
+			}
+		}
+		values := getUpdatedRecordsList(zone, recordType, ownedValues, current, newValues)
+
+		switch {
+		case len(values) == 0 && exists:
+			err = c.call(ctx, func() error {
+				return c.LiveDNS.DeleteDomainRecord(zone, name, recordType)
+			})
+		case len(values) == 0:
+		case !exists:
+			err = c.callNonIdempotent(ctx, func() error {
+				_, err := c.LiveDNS.CreateDomainRecord(zone, name, recordType, ttl, splitTXTValues(recordType, values))
+				return err
+			})
+		default:
+			err = c.call(ctx, func() error {
+				_, err := c.LiveDNS.UpdateDomainRecordByNameAndType(zone, name, recordType, ttl, splitTXTValues(recordType, values))
+				return err
+			})
+		}
+		if err != nil {
+			requestError, ok := err.(*types.RequestError)
+			if ok && (requestError.StatusCode == 404 || requestError.StatusCode == 409) {
+				conflict = fmt.Sprintf("it was created or deleted concurrently: %s", err)
+				continue
+			}
+			return err
+		}
+
+		after, _, err := getRecordValues(ctx, c, zone, name, recordType)
+		if err != nil {
+			return err
+		}
+		if !ownedValuesApplied(zone, recordType, after, ownedValues, newValues) {
+			conflict = fmt.Sprintf("its values were overwritten by %v", after)
+			continue
+		}
+		if missing := missingOtherValues(zone, recordType, after, current, ownedValues); len(missing) > 0 {
+			conflict = fmt.Sprintf("the values %v of other writers were removed concurrently", missing)
+			continue
+		}
+		return nil
+	}
+	return fmt.Errorf("failed to update the record %s %s of zone %s after %d attempts because it is modified concurrently: the last conflict is that %s",
+		name, recordType, zone, mutableRecordMaxAttempts, conflict)
+}
@@ -1,16 +1,24 @@
 package gandi
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"reflect"
 	"sort"
+	"strings"
+	"sync"
 	"testing"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
 
 	"github.com/go-gandi/go-gandi"
 	"github.com/go-gandi/go-gandi/config"
+	"github.com/go-gandi/go-gandi/livedns"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
@@ -175,14 +183,14 @@ func TestRemoveRecordFromValuesList(t *testing.T) {
 	})
 }
 
-func TestGetUpdatedTXTRecordsList(t *testing.T) {
+func TestGetUpdatedRecordsList(t *testing.T) {
 	currentStateRecords := []string{"192.168.1.1", "192.168.1.2", "192.168.1.3"}
 	managedByHandRecords := []string{"10.10.10.10", "0.0.0.0"}
-	apiRecords := append(managedByHandRecords, currentStateRecords...)
+	apiRecords := wrapRecordsWithQuotes(append(managedByHandRecords, currentStateRecords...))
 
 	t.Run("remove records in terraform", func(t *testing.T) {
 		nextStateRecords := []string{"192.168.1.1"}
-		updatedRecordsList := getUpdatedTXTRecordsList(currentStateRecords, apiRecords, nextStateRecords)
+		updatedRecordsList := getUpdatedRecordsList("example.com", TXT, currentStateRecords, apiRecords, nextStateRecords)
 		awaitedRecordsList := wrapRecordsWithQuotes(append(managedByHandRecords, nextStateRecords...))
 		if !areStringSlicesEqual(updatedRecordsList, awaitedRecordsList) {
 			t.Errorf("records list should only contains records managed by hand and new terraform records")
@@ -191,15 +199,24 @@ func TestGetUpdatedTXTRecordsList(t *testing.T) {
 
 	t.Run("add records in terraform", func(t *testing.T) {
 		nextStateRecords := append(currentStateRecords, "192.168.1.4")
-		updatedRecordsList := getUpdatedTXTRecordsList(currentStateRecords, apiRecords, nextStateRecords)
+		updatedRecordsList := getUpdatedRecordsList("example.com", TXT, currentStateRecords, apiRecords, nextStateRecords)
 		awaitedRecordsList := wrapRecordsWithQuotes(append(managedByHandRecords, nextStateRecords...))
 		if !areStringSlicesEqual(updatedRecordsList, awaitedRecordsList) {
 			t.Errorf("records list should only contains records managed by hand and new terraform records")
 		}
 	})
+
+	t.Run("keep records of others as they are", func(t *testing.T) {
+		apiRecords := []string{"ns1.example.com.", "NS.Other.net.", "ns3"}
+		updatedRecordsList := getUpdatedRecordsList("example.com", "NS", []string{"ns1"}, apiRecords, []string{"NS2"})
+		awaitedRecordsList := []string{"ns2.example.com.", "NS.Other.net.", "ns3"}
+		if !areStringSlicesEqual(updatedRecordsList, awaitedRecordsList) {
+			t.Errorf("records list should be %v, got %v", awaitedRecordsList, updatedRecordsList)
+		}
+	})
 }
 
-func TestKeepRecordsInApiAndTF(t *testing.T) {
+func TestKeepOwnedRecordValues(t *testing.T) {
 	terraformRecords := []string{"192.168.1.1", "192.168.1.2", "192.168.1.3"}
 	managedByHandRecords := []string{"10.10.10.10", "0.0.0.0"}
 	apiRecords := append(terraformRecords, managedByHandRecords...)
@@ -208,7 +225,7 @@ func TestKeepRecordsInApiAndTF(t *testing.T) {
 	t.Run("remove terraform record by hand", func(t *testing.T) {
 		awaitedRecords := terraformRecords[1:]
 		// api returns records wrapped with quotes
-		recordsInBoth := keepRecordsInApiAndTF(terraformRecords, apiRecordsWithQuotes[1:])
+		recordsInBoth := keepOwnedRecordValues("example.com", TXT, terraformRecords, apiRecordsWithQuotes[1:])
 		if !areStringSlicesEqual(recordsInBoth, awaitedRecords) {
 			t.Errorf("should only contains values that are both in api and terraform")
 		}
@@ -315,3 +332,609 @@ func TestAccRecord_mutable(t *testing.T) {
 		},
 	})
 }
+
+func testOfflineRecordConfig(values ...interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"zone":   "example.com",
+		"name":   "www",
+		"type":   "A",
+		"ttl":    3600,
+		"values": values,
+	}
+}
+
+func TestOfflineRecord_basic(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addZone("example.com")
+	p := newTestOfflineProvider(t, api)
+
+	state := p.apply("gandi_livedns_record", nil, testOfflineRecordConfig("192.168.0.1"))
+	testCheckAttributes(t, state, map[string]string{
+		"id":       "example.com/www/A",
+		"ttl":      "3600",
+		"values.#": "1",
+	})
+	p.planEmpty("gandi_livedns_record", state, testOfflineRecordConfig("192.168.0.1"))
+
+	state = p.apply("gandi_livedns_record", state, testOfflineRecordConfig("192.168.0.1", "192.168.0.2"))
+	if values := api.record("example.com", "www", "A"); !areStringSlicesEqual(values, []string{"192.168.0.1", "192.168.0.2"}) {
+		t.Fatalf("unexpected values on the API: %v", values)
+	}
+
+	imported := p.importState("gandi_livedns_record", "example.com/www/A")
+	testCheckAttributes(t, imported, map[string]string{
+		"zone":     "example.com",
+		"name":     "www",
+		"type":     "A",
+		"values.#": "2",
+	})
+
+	p.destroy("gandi_livedns_record", state)
+	if values := api.record("example.com", "www", "A"); values != nil {
+		t.Fatalf("the record should have been deleted, got %v", values)
+	}
+}
+
+// TestOfflineRecord_manually_removed is the offline counterpart of
+// TestAccRecord_manually_removed.
+func TestOfflineRecord_manually_removed(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addZone("example.com")
+	p := newTestOfflineProvider(t, api)
+
+	state := p.apply("gandi_livedns_record", nil, testOfflineRecordConfig("192.168.0.1"))
+	api.deleteRecord("example.com", "www", "A")
+	if state = p.refresh("gandi_livedns_record", state); state != nil {
+		t.Fatalf("the record should have been removed from the state")
+	}
+	p.apply("gandi_livedns_record", state, testOfflineRecordConfig("192.168.0.1"))
+	if values := api.record("example.com", "www", "A"); values == nil {
+		t.Fatalf("the record should have been recreated")
+	}
+}
+
+// TestOfflineRecord_mutable is the offline counterpart of
+// TestAccRecord_mutable.
+func TestOfflineRecord_mutable(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addZone("example.com")
+	p := newTestOfflineProvider(t, api)
+	config := map[string]interface{}{
+		"zone":    "example.com",
+		"name":    "mutable",
+		"type":    "TXT",
+		"ttl":     3600,
+		"mutable": true,
+		"values":  []interface{}{"terraform-1"},
+	}
+
+	state := p.apply("gandi_livedns_record", nil, config)
+	api.setRecord("example.com", livedns.DomainRecord{
+		RrsetName:   "mutable",
+		RrsetType:   "TXT",
+		RrsetTTL:    3600,
+		RrsetValues: []string{"terraform-1", "manual-1"},
+	})
+	state = p.refresh("gandi_livedns_record", state)
+	testCheckAttributes(t, state, map[string]string{
+		"values.#": "1",
+	})
+	p.planEmpty("gandi_livedns_record", state, config)
+
+	p.destroy("gandi_livedns_record", state)
+	if values := api.record("example.com", "mutable", "TXT"); !areStringSlicesEqual(values, []string{"\"manual-1\""}) {
+		t.Fatalf("the manually added value should have been kept, got %v", values)
+	}
+}
+
+func TestCanonicalRecordValue(t *testing.T) {
+	valid := []struct {
+		recordType, value, expected string
+	}{
+		{"A", "192.168.0.1", "192.168.0.1"},
+		{"AAAA", "2001:DB8:0:0:0:0:0:1", "2001:db8::1"},
+		{"CNAME", "WWW.Example.org.", "www.example.org."},
+		{"CNAME", "www", "www.example.com."},
+		{"NS", "@", "example.com."},
+		{"MX", "10  Mail.example.com.", "10 mail.example.com."},
+		{"SRV", "10 5 0443 sip.example.com.", "10 5 443 sip.example.com."},
+		{"CAA", "0 ISSUE letsencrypt.org", "0 issue \"letsencrypt.org\""},
+		{"TXT", "v=spf1 -all", "\"v=spf1 -all\""},
+		{"TLSA", "3 1 1 ABCDEF", "3 1 1 abcdef"},
+		{"SSHFP", "1 2 ABCDEF", "1 2 abcdef"},
+		{"DS", "12345 13 2 ABCDEF", "12345 13 2 abcdef"},
+		{"LOC", "52 22 23.000 N  4 53 32.000 E -2.00m", "52 22 23.000 N 4 53 32.000 E -2.00m"},
+	}
+	for _, tc := range valid {
+		canonical, err := canonicalRecordValue("example.com", tc.recordType, tc.value)
+		if err != nil {
+			t.Errorf("%s %q: unexpected error: %s", tc.recordType, tc.value, err)
+		} else if canonical != tc.expected {
+			t.Errorf("%s %q: expected %q, got %q", tc.recordType, tc.value, tc.expected, canonical)
+		}
+	}
+
+	invalid := []struct {
+		recordType, value string
+	}{
+		{"A", "2001:db8::1"},
+		{"A", "192.168.0"},
+		{"AAAA", "192.168.0.1"},
+		{"CNAME", "not a hostname"},
+		{"MX", "mail.example.com."},
+		{"MX", "70000 mail.example.com."},
+		{"SRV", "10 5 sip.example.com."},
+		{"CAA", "256 issue letsencrypt.org"},
+		{"TLSA", "3 1 1 xyz"},
+		{"SSHFP", "1 abcdef"},
+		{"DS", "12345 13 2"},
+		{"TXT", " "},
+	}
+	for _, tc := range invalid {
+		if _, err := canonicalRecordValue("example.com", tc.recordType, tc.value); err == nil {
+			t.Errorf("%s %q: expected an error", tc.recordType, tc.value)
+		}
+	}
+}
+
+func TestOfflineRecord_invalid(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addZone("example.com")
+	p := newTestOfflineProvider(t, api)
+
+	for _, config := range []map[string]interface{}{
+		testOfflineRecordConfig("2001:db8::1"),
+		{"zone": "example.com", "name": "www", "type": "MXX", "ttl": 300, "values": []interface{}{"10 mail"}},
+		{"zone": "example.com", "name": "www", "type": "MX", "ttl": 300, "values": []interface{}{"mail"}},
+		{"zone": "example.com", "name": "www", "type": "CNAME", "ttl": 300, "values": []interface{}{"a", "b"}},
+	} {
+		if _, diags := p.plan("gandi_livedns_record", nil, config); !diags.HasError() {
+			t.Errorf("expected the plan of %v to fail", config)
+		}
+	}
+	if n := api.requestCount("POST"); n != 0 {
+		t.Fatalf("no request should have been sent, got %d", n)
+	}
+}
+
+// TestOfflineRecord_lowerCaseType checks the type of a record is
+// accepted whatever its case.
+func TestOfflineRecord_lowerCaseType(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addZone("example.com")
+	p := newTestOfflineProvider(t, api)
+	config := map[string]interface{}{
+		"zone":   "example.com",
+		"name":   "@",
+		"type":   "mx",
+		"ttl":    3600,
+		"values": []interface{}{"10 mail"},
+	}
+
+	state := p.apply("gandi_livedns_record", nil, config)
+	if values := api.record("example.com", "@", "MX"); !areStringSlicesEqual(values, []string{"10 mail"}) {
+		t.Fatalf("unexpected values on the API: %v", values)
+	}
+	testCheckAttributes(t, state, map[string]string{"type": "MX"})
+	p.planEmpty("gandi_livedns_record", p.refresh("gandi_livedns_record", state), config)
+
+	config["values"] = []interface{}{"mail"}
+	if _, diags := p.plan("gandi_livedns_record", nil, config); !diags.HasError() {
+		t.Fatalf("the values of lower case types should be validated")
+	}
+}
+
+// TestOfflineRecord_unknownValues checks the values only known at apply
+// time are skipped from the validation while the other ones are still
+// validated.
+func TestOfflineRecord_unknownValues(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addZone("example.com")
+	p := newTestOfflineProvider(t, api)
+	r := p.resource("gandi_livedns_record")
+	rawConfig := func(values ...cty.Value) *terraform.InstanceState {
+		return &terraform.InstanceState{RawConfig: cty.ObjectVal(map[string]cty.Value{
+			"values": cty.SetVal(append(values, cty.UnknownVal(cty.String))),
+		})}
+	}
+
+	config := testOfflineRecordConfig("192.168.1.1")
+	if _, err := r.Diff(context.Background(), rawConfig(cty.StringVal("192.168.1.1")), terraform.NewResourceConfigRaw(config), p.provider.Meta()); err != nil {
+		t.Fatalf("the known values are valid, got %s", err)
+	}
+	config = testOfflineRecordConfig("2001:db8::1")
+	if _, err := r.Diff(context.Background(), rawConfig(cty.StringVal("2001:db8::1")), terraform.NewResourceConfigRaw(config), p.provider.Meta()); err == nil {
+		t.Fatalf("the known values should still be validated")
+	}
+}
+
+// TestOfflineRecord_canonical checks the API canonical form of the
+// values doesn't produce a diff.
+func TestOfflineRecord_canonical(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addZone("example.com")
+	p := newTestOfflineProvider(t, api)
+	config := map[string]interface{}{
+		"zone":   "example.com",
+		"name":   "www",
+		"type":   "AAAA",
+		"ttl":    3600,
+		"values": []interface{}{"2001:DB8:0:0::1"},
+	}
+
+	state := p.apply("gandi_livedns_record", nil, config)
+	api.setRecord("example.com", livedns.DomainRecord{RrsetName: "www", RrsetType: "AAAA", RrsetTTL: 3600, RrsetValues: []string{"2001:db8::1"}})
+	state = p.refresh("gandi_livedns_record", state)
+	p.planEmpty("gandi_livedns_record", state, config)
+
+	config["values"] = []interface{}{"2001:db8::2"}
+	if diff, _ := p.plan("gandi_livedns_record", state, config); diff == nil || diff.Empty() {
+		t.Fatalf("expected a diff when the values change")
+	}
+}
+
+func TestOfflineRecord_blocks(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addZone("example.com")
+	p := newTestOfflineProvider(t, api)
+	config := map[string]interface{}{
+		"zone": "example.com",
+		"name": "@",
+		"type": "MX",
+		"ttl":  3600,
+		"mx": []interface{}{
+			map[string]interface{}{"priority": 10, "host": "mx1.example.com."},
+			map[string]interface{}{"priority": 20, "host": "mx2.example.com."},
+		},
+	}
+
+	state := p.apply("gandi_livedns_record", nil, config)
+	if values := api.record("example.com", "@", "MX"); !areStringSlicesEqual(values, []string{"10 mx1.example.com.", "20 mx2.example.com."}) {
+		t.Fatalf("unexpected values on the API: %v", values)
+	}
+	testCheckAttributes(t, state, map[string]string{
+		"values.#": "2",
+		"mx.#":     "2",
+	})
+	p.planEmpty("gandi_livedns_record", state, config)
+
+	config["mx"] = []interface{}{
+		map[string]interface{}{"priority": 5, "host": "mx1.example.com."},
+	}
+	state = p.apply("gandi_livedns_record", state, config)
+	if values := api.record("example.com", "@", "MX"); !areStringSlicesEqual(values, []string{"5 mx1.example.com."}) {
+		t.Fatalf("unexpected values on the API: %v", values)
+	}
+	p.planEmpty("gandi_livedns_record", state, config)
+
+	imported := p.importState("gandi_livedns_record", "example.com/@/MX")
+	testCheckAttributes(t, imported, map[string]string{
+		"mx.#":   "1",
+		"caa.#":  "0",
+		"srv.#":  "0",
+		"tlsa.#": "0",
+	})
+
+	// The blocks are also computed from the values
+	caa := p.apply("gandi_livedns_record", nil, map[string]interface{}{
+		"zone":   "example.com",
+		"name":   "@",
+		"type":   "CAA",
+		"ttl":    3600,
+		"values": []interface{}{"0 issue \"letsencrypt.org\""},
+	})
+	for k, v := range caa.Attributes {
+		if strings.HasPrefix(k, "caa.") && strings.HasSuffix(k, ".value") && v != "letsencrypt.org" {
+			t.Fatalf("unexpected CAA value %q", v)
+		}
+	}
+	testCheckAttributes(t, caa, map[string]string{"caa.#": "1"})
+}
+
+func TestOfflineRecord_blocks_invalid(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addZone("example.com")
+	p := newTestOfflineProvider(t, api)
+	srv := []interface{}{
+		map[string]interface{}{"priority": 10, "weight": 0, "port": 5060, "target": "sip.example.com."},
+	}
+
+	for _, config := range []map[string]interface{}{
+		{"zone": "example.com", "name": "www", "type": "MX", "ttl": 300, "srv": srv},
+		{"zone": "example.com", "name": "www", "type": "SRV", "ttl": 300, "srv": srv, "values": []interface{}{"10 0 5060 sip"}},
+		{"zone": "example.com", "name": "www", "type": "SRV", "ttl": 300},
+	} {
+		if _, diags := p.plan("gandi_livedns_record", nil, config); !diags.HasError() {
+			t.Errorf("expected the plan of %v to fail", config)
+		}
+	}
+}
+
+func TestSplitTXTValue(t *testing.T) {
+	long := strings.Repeat("a", 300)
+	split := splitTXTValue(long)
+	if expected := "\"" + strings.Repeat("a", 255) + "\" \"" + strings.Repeat("a", 45) + "\""; split != expected {
+		t.Fatalf("expected %q, got %q", expected, split)
+	}
+	if joined := joinTXTValue(split); joined != "\""+long+"\"" {
+		t.Fatalf("expected the joined value to be the quoted value, got %q", joined)
+	}
+	if split := splitTXTValue("\"" + long + "\""); split != splitTXTValue(long) {
+		t.Fatalf("quoted and unquoted values should be split the same way, got %q", split)
+	}
+
+	// Values which don't need to be split are kept as is
+	for _, v := range []string{"short", "\"short\"", "\"already\" \"split\""} {
+		if split := splitTXTValue(v); split != v {
+			t.Errorf("expected %q to be kept as is, got %q", v, split)
+		}
+	}
+
+	// Escape sequences and multi-byte characters are not split
+	escaped := strings.Repeat("a", 254) + "\\\"" + "b"
+	for _, chunk := range parseTXTChunks(splitTXTValue(escaped)) {
+		if strings.HasSuffix(chunk, "\\") {
+			t.Fatalf("an escape sequence has been split: %q", chunk)
+		}
+	}
+	multibyte := strings.Repeat("a", 254) + "é"
+	if chunks := parseTXTChunks(splitTXTValue(multibyte)); len(chunks) != 2 || chunks[1] != "é" {
+		t.Fatalf("a multi-byte character has been split: %q", chunks)
+	}
+}
+
+func TestOfflineRecord_longTXT(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addZone("example.com")
+	p := newTestOfflineProvider(t, api)
+	dkim := "v=DKIM1; k=rsa; p=" + strings.Repeat("MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEA", 8)
+	config := map[string]interface{}{
+		"zone":   "example.com",
+		"name":   "default._domainkey",
+		"type":   "TXT",
+		"ttl":    3600,
+		"values": []interface{}{dkim},
+	}
+
+	state := p.apply("gandi_livedns_record", nil, config)
+	values := api.record("example.com", "default._domainkey", "TXT")
+	if len(values) != 1 || len(parseTXTChunks(values[0])) != 2 {
+		t.Fatalf("the value should have been split in two strings on the API, got %v", values)
+	}
+	p.planEmpty("gandi_livedns_record", p.refresh("gandi_livedns_record", state), config)
+}
+
+// TestOfflineRecord_mutable_shared checks two workspaces can each own
+// some values of a same record.
+func TestOfflineRecord_mutable_shared(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addZone("example.com")
+	first := newTestOfflineProvider(t, api)
+	second := newTestOfflineProvider(t, api)
+	config := func(values ...interface{}) map[string]interface{} {
+		c := testOfflineRecordConfig(values...)
+		c["mutable"] = true
+		return c
+	}
+
+	firstState := first.apply("gandi_livedns_record", nil, config("192.168.0.1"))
+	secondState := second.apply("gandi_livedns_record", nil, config("192.168.0.2"))
+	if values := api.record("example.com", "www", "A"); !areStringSlicesEqual(values, []string{"192.168.0.1", "192.168.0.2"}) {
+		t.Fatalf("both values should be on the API, got %v", values)
+	}
+
+	firstState = first.refresh("gandi_livedns_record", firstState)
+	testCheckAttributes(t, firstState, map[string]string{"values.#": "1"})
+	first.planEmpty("gandi_livedns_record", firstState, config("192.168.0.1"))
+
+	firstState = first.apply("gandi_livedns_record", firstState, config("192.168.0.3"))
+	if values := api.record("example.com", "www", "A"); !areStringSlicesEqual(values, []string{"192.168.0.3", "192.168.0.2"}) {
+		t.Fatalf("only the value of the first workspace should have been replaced, got %v", values)
+	}
+
+	first.destroy("gandi_livedns_record", firstState)
+	if values := api.record("example.com", "www", "A"); !areStringSlicesEqual(values, []string{"192.168.0.2"}) {
+		t.Fatalf("the value of the second workspace should have been kept, got %v", values)
+	}
+	second.destroy("gandi_livedns_record", second.refresh("gandi_livedns_record", secondState))
+	if values := api.record("example.com", "www", "A"); values != nil {
+		t.Fatalf("the record should have been deleted, got %v", values)
+	}
+}
+
+// TestOfflineRecord_mutable_owned checks a value is owned by a single
+// resource.
+func TestOfflineRecord_mutable_owned(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addZone("example.com")
+	first := newTestOfflineProvider(t, api)
+	second := newTestOfflineProvider(t, api)
+	config := func(values ...interface{}) map[string]interface{} {
+		c := testOfflineRecordConfig(values...)
+		c["mutable"] = true
+		return c
+	}
+
+	firstState := first.apply("gandi_livedns_record", nil, config("192.168.0.1"))
+	if _, diags := second.applyWithDiags("gandi_livedns_record", nil, config("192.168.0.1")); !diags.HasError() {
+		t.Fatalf("adding a value owned by another resource should fail")
+	}
+	secondState := second.apply("gandi_livedns_record", nil, config("192.168.0.2"))
+	if _, diags := second.applyWithDiags("gandi_livedns_record", secondState, config("192.168.0.2", "192.168.0.1")); !diags.HasError() {
+		t.Fatalf("adding a value owned by another resource on update should fail")
+	}
+
+	first.destroy("gandi_livedns_record", firstState)
+	if values := api.record("example.com", "www", "A"); !areStringSlicesEqual(values, []string{"192.168.0.2"}) {
+		t.Fatalf("only the value of the first resource should have been removed, got %v", values)
+	}
+	// Once removed, the value can be added by another resource
+	second.apply("gandi_livedns_record", second.refresh("gandi_livedns_record", secondState), config("192.168.0.2", "192.168.0.1"))
+	if values := api.record("example.com", "www", "A"); !areStringSlicesEqual(values, []string{"192.168.0.2", "192.168.0.1"}) {
+		t.Fatalf("the value should have been added, got %v", values)
+	}
+}
+
+func TestOfflineRecord_mutable_conflict(t *testing.T) {
+	delay := mutableRecordRetryDelay
+	mutableRecordRetryDelay = 0
+	t.Cleanup(func() { mutableRecordRetryDelay = delay })
+
+	api := newFakeGandiAPI(t)
+	api.addZone("example.com", livedns.DomainRecord{RrsetName: "www", RrsetType: "A", RrsetValues: []string{"192.168.0.1"}})
+	p := newTestOfflineProvider(t, api)
+	config := testOfflineRecordConfig("192.168.0.2")
+	config["mutable"] = true
+
+	// Another writer replaces the value of a third one right after the
+	// record is written: the values are merged again from the record
+	replaced := false
+	api.beforeRequest = func(r *http.Request) {
+		if r.Method == http.MethodGet && len(api.requests) > 1 && api.requests[len(api.requests)-2] == "PUT livedns/domains/example.com/records/www/A" && !replaced {
+			replaced = true
+			zone := api.zones["example.com"]
+			i, _ := zone.findRecord("www", "A")
+			zone.records[i].RrsetValues = []string{"192.168.0.2", "192.168.0.3"}
+		}
+	}
+	state := p.apply("gandi_livedns_record", nil, config)
+	if count := api.requestCount("PUT livedns/domains/example.com/records/www/A"); count != 2 {
+		t.Fatalf("expected the record to be written again once the value of the other writer was removed, got %d writes", count)
+	}
+	if values := api.record("example.com", "www", "A"); !areStringSlicesEqual(values, []string{"192.168.0.2", "192.168.0.3"}) {
+		t.Fatalf("the concurrent replacement should have been kept, got %v", values)
+	}
+
+	// Another writer overwrites the record right after it is written
+	overwritten := false
+	api.beforeRequest = func(r *http.Request) {
+		if r.Method == http.MethodGet && api.requests[len(api.requests)-2] == "PUT livedns/domains/example.com/records/www/A" && !overwritten {
+			overwritten = true
+			zone := api.zones["example.com"]
+			i, _ := zone.findRecord("www", "A")
+			zone.records[i].RrsetValues = []string{"192.168.0.1", "192.168.0.3"}
+		}
+	}
+	config["values"] = []interface{}{"192.168.0.4"}
+	state = p.apply("gandi_livedns_record", state, config)
+	if !overwritten {
+		t.Fatalf("the record should have been overwritten")
+	}
+	if values := api.record("example.com", "www", "A"); !areStringSlicesEqual(values, []string{"192.168.0.1", "192.168.0.3", "192.168.0.4"}) {
+		t.Fatalf("the overwritten value should have been written again, got %v", values)
+	}
+
+	// The update fails when the record keeps being overwritten
+	api.beforeRequest = func(r *http.Request) {
+		if r.Method == http.MethodGet && len(api.requests) > 1 && strings.HasPrefix(api.requests[len(api.requests)-2], "PUT ") {
+			zone := api.zones["example.com"]
+			i, _ := zone.findRecord("www", "A")
+			zone.records[i].RrsetValues = []string{fmt.Sprintf("10.0.0.%d", len(api.requests)%250)}
+		}
+	}
+	config["values"] = []interface{}{"192.168.0.5"}
+	_, diags := p.applyWithDiags("gandi_livedns_record", state, config)
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "modified concurrently") {
+		t.Fatalf("expected a concurrent modification error, got %v", diags)
+	}
+}
+
+func TestOfflineRecord_mutable_canceled(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addZone("example.com", livedns.DomainRecord{RrsetName: "www", RrsetType: "A", RrsetValues: []string{"192.168.0.1"}})
+	p := newTestOfflineProvider(t, api)
+
+	// The record keeps changing and the operation is canceled while
+	// it is read: no other request is sent
+	ctx, cancel := context.WithCancel(context.Background())
+	api.beforeRequest = func(r *http.Request) {
+		if r.Method == http.MethodGet {
+			zone := api.zones["example.com"]
+			i, _ := zone.findRecord("www", "A")
+			zone.records[i].RrsetValues = append(zone.records[i].RrsetValues, fmt.Sprintf("10.0.0.%d", len(api.requests)%250))
+			cancel()
+		}
+	}
+	c := p.provider.Meta().(*clients)
+	err := updateMutableRecord(ctx, c, "example.com", "www", "A", 300, nil, []string{"192.168.0.2"})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the update to be canceled, got %v", err)
+	}
+	if count := api.requestCount("GET livedns/domains/example.com/records/www/A"); count != 1 {
+		t.Fatalf("no other attempt should have been made, got %d reads", count)
+	}
+}
+
+// TestOfflineRecord_concurrent checks the records of a zone applied
+// concurrently, as Terraform does, are each written with their own
+// request, without replacing the other records of the zone.
+func TestOfflineRecord_concurrent(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addZone("example.com",
+		livedns.DomainRecord{RrsetName: "www0", RrsetType: "A", RrsetTTL: 300, RrsetValues: []string{"192.0.2.254"}},
+		livedns.DomainRecord{RrsetName: "mail", RrsetType: "A", RrsetTTL: 300, RrsetValues: []string{"192.0.2.253"}},
+	)
+	p := newTestOfflineProvider(t, api)
+
+	configs := func(value string) []map[string]interface{} {
+		var configs []map[string]interface{}
+		for i := 0; i < 10; i++ {
+			configs = append(configs, map[string]interface{}{
+				"zone":   "example.com",
+				"name":   fmt.Sprintf("www%d", i),
+				"type":   "A",
+				"ttl":    300,
+				"values": []interface{}{fmt.Sprintf(value, i)},
+			})
+		}
+		return configs
+	}
+	apply := func(states []*terraform.InstanceState, configs []map[string]interface{}) []diag.Diagnostics {
+		diags := make([]diag.Diagnostics, len(configs))
+		var wg sync.WaitGroup
+		for i := range configs {
+			i := i
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				states[i], diags[i] = p.applyWithDiags("gandi_livedns_record", states[i], configs[i])
+			}()
+		}
+		wg.Wait()
+		return diags
+	}
+
+	// The creation of the existing www0 record fails on its own
+	states := make([]*terraform.InstanceState, 10)
+	diags := apply(states, configs("192.0.2.%d"))
+	if !diags[0].HasError() {
+		t.Fatalf("expected the creation of the existing record to fail")
+	}
+	for i, d := range diags[1:] {
+		if d.HasError() {
+			t.Fatalf("failed to create the record www%d: %v", i+1, d)
+		}
+	}
+	if count := api.exactRequestCount("PUT livedns/domains/example.com/records"); count != 0 {
+		t.Fatalf("expected the records of the zone not to be replaced, got %d requests", count)
+	}
+	if count := api.requestCount("POST livedns/domains/example.com/records"); count != 10 {
+		t.Fatalf("expected each record to be created with its own request, got %d requests", count)
+	}
+	if values := api.record("example.com", "www0", "A"); len(values) != 1 || values[0] != "192.0.2.254" {
+		t.Fatalf("expected the existing record to be kept, got %v", values)
+	}
+
+	states = states[1:]
+	for i, d := range apply(states, configs("198.51.100.%d")[1:]) {
+		if d.HasError() {
+			t.Fatalf("failed to update the record www%d: %v", i+1, d)
+		}
+	}
+	if values := api.record("example.com", "www9", "A"); len(values) != 1 || values[0] != "198.51.100.9" {
+		t.Fatalf("expected the record www9 to be updated, got %v", values)
+	}
+	if values := api.record("example.com", "mail", "A"); len(values) != 1 {
+		t.Fatalf("expected the other records of the zone to be kept, got %v", values)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+	"unicode/utf8"
 )
 
 func isRecordWrappedWithQuotes(record string) bool {
@@ -27,22 +28,16 @@ func keepUniqueRecords(recordsList []string) []string {
 	return uniqueRecords
 }
 
-func keepRecordsInApiAndTF(tfValues []string, apiValues []string) []string {
-	var apiRecordsWithoutQuotes []string
-	for _, v := range apiValues {
-		if isRecordWrappedWithQuotes(v) {
-			apiRecordsWithoutQuotes = append(apiRecordsWithoutQuotes, strings.Trim(v, "\""))
-		} else {
-			apiRecordsWithoutQuotes = append(apiRecordsWithoutQuotes, v)
-		}
-	}
+// keepOwnedRecordValues returns the values owned by a mutable record,
+// as written in Terraform, which are still in the API values. Values
+// are compared by their canonical form.
+func keepOwnedRecordValues(zone, recordType string, ownedValues []string, apiValues []string) []string {
+	apiCanonical := canonicalRecordValues(zone, recordType, apiValues)
 
 	var values []string
-	for _, tfv := range tfValues {
-		for _, apiv := range apiRecordsWithoutQuotes {
-			if tfv == apiv {
-				values = append(values, apiv)
-			}
+	for _, v := range ownedValues {
+		if _, exists := containsRecord(apiCanonical, canonicalRecordValues(zone, recordType, []string{v})[0]); exists {
+			values = append(values, v)
 		}
 	}
 	return values
@@ -83,16 +78,124 @@ func areStringSlicesEqual(a, b []string) bool {
 	return reflect.DeepEqual(a_copy, b_copy)
 }
 
-func getUpdatedTXTRecordsList(stateRecords, apiRecords, newRecords []string) []string {
-	currentRecordsWithQuotes := wrapRecordsWithQuotes(stateRecords)
-	apiRecordsWithQuotes := wrapRecordsWithQuotes(apiRecords)
-	for _, v := range currentRecordsWithQuotes {
-		index, exists := containsRecord(apiRecordsWithQuotes, v)
-		if exists {
-			apiRecordsWithQuotes = removeRecordFromValuesList(apiRecordsWithQuotes, index)
+// getUpdatedRecordsList returns the values of a mutable record once the
+// values previously owned by Terraform are replaced by the new ones.
+// Values are compared by their canonical form: the API values matching
+// neither a previously owned value nor a new one belong to others and
+// are kept byte for byte, as they are in the API.
+func getUpdatedRecordsList(zone, recordType string, stateRecords, apiRecords, newRecords []string) []string {
+	records := keepUniqueRecords(canonicalRecordValues(zone, recordType, newRecords))
+	replacedRecords := append(canonicalRecordValues(zone, recordType, stateRecords), records...)
+	for _, v := range apiRecords {
+		canonical := canonicalRecordValues(zone, recordType, []string{v})[0]
+		if _, exists := containsRecord(replacedRecords, canonical); !exists {
+			records = append(records, v)
 		}
 	}
+	return records
+}
 
-	records := append(wrapRecordsWithQuotes(newRecords), apiRecordsWithQuotes...)
-	return keepUniqueRecords(records)
+// txtChunkMaxLength is the maximum length of a character-string in a
+// TXT record
+const txtChunkMaxLength = 255
+
+func isTXTRecordType(recordType string) bool {
+	return recordType == TXT || recordType == "SPF"
+}
+
+// parseTXTChunks returns the content of the quoted character-strings
+// a TXT value is made of, or nil if the value is not only made of
+// quoted strings.
+func parseTXTChunks(value string) []string {
+	var chunks []string
+	rest := strings.TrimSpace(value)
+	for rest != "" {
+		if rest[0] != '"' {
+			return nil
+		}
+		end := -1
+		for i := 1; i < len(rest); i++ {
+			if rest[i] == '\\' {
+				i++
+			} else if rest[i] == '"' {
+				end = i
+				break
+			}
+		}
+		if end < 0 {
+			return nil
+		}
+		chunks = append(chunks, rest[1:end])
+		rest = strings.TrimLeft(rest[end+1:], " ")
+	}
+	return chunks
+}
+
+// joinTXTValue joins a TXT value split into several character-strings
+// into a single quoted string.
+func joinTXTValue(value string) string {
+	chunks := parseTXTChunks(value)
+	if len(chunks) < 2 {
+		return value
+	}
+	return "\"" + strings.Join(chunks, "") + "\""
+}
+
+// splitTXTValue splits a TXT value longer than a character-string into
+// several quoted character-strings. Values which are already split are
+// kept as is.
+func splitTXTValue(value string) string {
+	content := value
+	if chunks := parseTXTChunks(value); len(chunks) > 1 {
+		return value
+	} else if len(chunks) == 1 {
+		content = chunks[0]
+	}
+	if len(content) <= txtChunkMaxLength {
+		return value
+	}
+
+	var chunks []string
+	for len(content) > txtChunkMaxLength {
+		end := txtChunkMaxLength
+		// Don't split a multi-byte character nor an escape sequence
+		for end > 0 && !utf8.RuneStart(content[end]) {
+			end--
+		}
+		backslashes := 0
+		for i := end - 1; i >= 0 && content[i] == '\\'; i-- {
+			backslashes++
+		}
+		if backslashes%2 == 1 {
+			end--
+		}
+		chunks = append(chunks, "\""+content[:end]+"\"")
+		content = content[end:]
+	}
+	chunks = append(chunks, "\""+content+"\"")
+	return strings.Join(chunks, " ")
+}
+
+// joinTXTValues joins the values of a TXT record read from the API
+func joinTXTValues(recordType string, values []string) []string {
+	if !isTXTRecordType(recordType) {
+		return values
+	}
+	joined := make([]string, 0, len(values))
+	for _, v := range values {
+		joined = append(joined, joinTXTValue(v))
+	}
+	return joined
+}
+
+// splitTXTValues splits the values of a TXT record sent to the API
+func splitTXTValues(recordType string, values []string) []string {
+	if !isTXTRecordType(recordType) {
+		return values
+	}
+	split := make([]string, 0, len(values))
+	for _, v := range values {
+		split = append(split, splitTXTValue(v))
+	}
+	return split
 }
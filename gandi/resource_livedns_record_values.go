@@ -0,0 +1,287 @@
+package gandi
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// liveDNSRecordTypes are the record types supported by LiveDNS
+var liveDNSRecordTypes = []string{
+	"A", "AAAA", "ALIAS", "CAA", "CDS", "CNAME", "DNAME", "DS", "KEY", "LOC", "MX", "NAPTR",
+	"NS", "OPENPGPKEY", "PTR", "RP", "SPF", "SRV", "SSHFP", "TLSA", TXT, "WKS",
+}
+
+// singleValueRecordTypes are the record types which can't have more
+// than one value
+var singleValueRecordTypes = []string{"ALIAS", "CNAME", "DNAME"}
+
+var hostnameLabelRegexp = regexp.MustCompile(`^(\*|[a-zA-Z0-9_]([a-zA-Z0-9_-]{0,61}[a-zA-Z0-9_])?)$`)
+
+var caaTagRegexp = regexp.MustCompile(`^[a-z0-9]+$`)
+
+func validateRecordType(val interface{}, key string) (warns []string, errs []error) {
+	v := strings.ToUpper(val.(string))
+	for _, t := range liveDNSRecordTypes {
+		if v == t {
+			return
+		}
+	}
+	errs = append(errs, fmt.Errorf("%q must be one of %s. Got %s", key, strings.Join(liveDNSRecordTypes, ", "), val))
+	return
+}
+
+// upperCaseRecordType stores the record types the way LiveDNS returns
+// them, so that lower case types in the configuration don't produce a
+// diff.
+func upperCaseRecordType(val interface{}) string {
+	return strings.ToUpper(val.(string))
+}
+
+// recordValueFields splits a value into its fields and checks their
+// count.
+func recordValueFields(value string, count int, format string) ([]string, error) {
+	fields := strings.Fields(value)
+	if len(fields) != count {
+		return nil, fmt.Errorf("the value should have the format '%s'", format)
+	}
+	return fields, nil
+}
+
+func canonicalUint(s string, bits int, name string) (string, error) {
+	n, err := strconv.ParseUint(s, 10, bits)
+	if err != nil {
+		return "", fmt.Errorf("the %s should be a number lower than %d", name, uint64(1)<<bits)
+	}
+	return strconv.FormatUint(n, 10), nil
+}
+
+func canonicalHex(s, name string) (string, error) {
+	if _, err := hex.DecodeString(s); err != nil {
+		return "", fmt.Errorf("the %s should be an hexadecimal string", name)
+	}
+	return strings.ToLower(s), nil
+}
+
+// canonicalHostname returns the lower-cased fully qualified form of a
+// hostname, with its trailing dot. Hostnames without a trailing dot
+// are relative to the zone, as in zone files.
+func canonicalHostname(zone, hostname string) (string, error) {
+	if hostname == "@" {
+		return strings.ToLower(strings.TrimSuffix(zone, ".")) + ".", nil
+	}
+	if hostname == "." {
+		return hostname, nil
+	}
+	name := strings.TrimSuffix(hostname, ".")
+	if len(name) > 253 {
+		return "", fmt.Errorf("the hostname %s is too long", hostname)
+	}
+	for _, label := range strings.Split(name, ".") {
+		if !hostnameLabelRegexp.MatchString(label) {
+			return "", fmt.Errorf("%s is not a valid hostname", hostname)
+		}
+	}
+	if !strings.HasSuffix(hostname, ".") {
+		name = name + "." + strings.TrimSuffix(zone, ".")
+	}
+	return strings.ToLower(name) + ".", nil
+}
+
+// canonicalRecordValue validates a value of a record of the given type
+// and returns its canonical form: two values with the same canonical
+// form are considered equal by LiveDNS.
+func canonicalRecordValue(zone, recordType, value string) (string, error) {
+	if strings.TrimSpace(value) == "" {
+		return "", fmt.Errorf("the value can't be empty")
+	}
+	switch recordType {
+	case "A":
+		ip := net.ParseIP(value)
+		if ip == nil || ip.To4() == nil || strings.Contains(value, ":") {
+			return "", fmt.Errorf("%s is not an IPv4 address", value)
+		}
+		return ip.String(), nil
+	case "AAAA":
+		ip := net.ParseIP(value)
+		if ip == nil || !strings.Contains(value, ":") {
+			return "", fmt.Errorf("%s is not an IPv6 address", value)
+		}
+		return ip.String(), nil
+	case "ALIAS", "CNAME", "DNAME", "NS", "PTR":
+		return canonicalHostname(zone, value)
+	case "MX":
+		fields, err := recordValueFields(value, 2, "{priority} {hostname}")
+		if err != nil {
+			return "", err
+		}
+		priority, err := canonicalUint(fields[0], 16, "priority")
+		if err != nil {
+			return "", err
+		}
+		hostname, err := canonicalHostname(zone, fields[1])
+		if err != nil {
+			return "", err
+		}
+		return priority + " " + hostname, nil
+	case "SRV":
+		fields, err := recordValueFields(value, 4, "{priority} {weight} {port} {target}")
+		if err != nil {
+			return "", err
+		}
+		for i, name := range []string{"priority", "weight", "port"} {
+			if fields[i], err = canonicalUint(fields[i], 16, name); err != nil {
+				return "", err
+			}
+		}
+		if fields[3], err = canonicalHostname(zone, fields[3]); err != nil {
+			return "", err
+		}
+		return strings.Join(fields, " "), nil
+	case "CAA":
+		fields := strings.SplitN(strings.TrimSpace(value), " ", 3)
+		if len(fields) != 3 {
+			return "", fmt.Errorf("the value should have the format '{flags} {tag} \"{value}\"'")
+		}
+		flags, err := canonicalUint(fields[0], 8, "flags")
+		if err != nil {
+			return "", err
+		}
+		tag := strings.ToLower(fields[1])
+		if !caaTagRegexp.MatchString(tag) {
+			return "", fmt.Errorf("%s is not a valid CAA tag", fields[1])
+		}
+		return flags + " " + tag + " " + wrapRecordsWithQuotes([]string{strings.TrimSpace(fields[2])})[0], nil
+	case "TLSA":
+		fields, err := recordValueFields(value, 4, "{usage} {selector} {matching type} {certificate data}")
+		if err != nil {
+			return "", err
+		}
+		for i, name := range []string{"usage", "selector", "matching type"} {
+			if fields[i], err = canonicalUint(fields[i], 8, name); err != nil {
+				return "", err
+			}
+		}
+		if fields[3], err = canonicalHex(fields[3], "certificate data"); err != nil {
+			return "", err
+		}
+		return strings.Join(fields, " "), nil
+	case "SSHFP":
+		fields, err := recordValueFields(value, 3, "{algorithm} {fingerprint type} {fingerprint}")
+		if err != nil {
+			return "", err
+		}
+		for i, name := range []string{"algorithm", "fingerprint type"} {
+			if fields[i], err = canonicalUint(fields[i], 8, name); err != nil {
+				return "", err
+			}
+		}
+		if fields[2], err = canonicalHex(fields[2], "fingerprint"); err != nil {
+			return "", err
+		}
+		return strings.Join(fields, " "), nil
+	case "DS", "CDS":
+		fields, err := recordValueFields(value, 4, "{key tag} {algorithm} {digest type} {digest}")
+		if err != nil {
+			return "", err
+		}
+		if fields[0], err = canonicalUint(fields[0], 16, "key tag"); err != nil {
+			return "", err
+		}
+		for i, name := range map[int]string{1: "algorithm", 2: "digest type"} {
+			if fields[i], err = canonicalUint(fields[i], 8, name); err != nil {
+				return "", err
+			}
+		}
+		if fields[3], err = canonicalHex(fields[3], "digest"); err != nil {
+			return "", err
+		}
+		return strings.Join(fields, " "), nil
+	case TXT, "SPF":
+		return wrapRecordsWithQuotes([]string{joinTXTValue(value)})[0], nil
+	default:
+		return strings.Join(strings.Fields(value), " "), nil
+	}
+}
+
+// canonicalRecordValues returns the canonical form of values. Values
+// which can't be canonicalized are kept as is.
+func canonicalRecordValues(zone, recordType string, values []string) []string {
+	canonical := make([]string, 0, len(values))
+	for _, v := range values {
+		if c, err := canonicalRecordValue(zone, recordType, v); err == nil {
+			v = c
+		}
+		canonical = append(canonical, v)
+	}
+	return canonical
+}
+
+// suppressEquivalentRecordValues suppresses the diff of the values of
+// a record when they have the same canonical form, such as compressed
+// and uncompressed IPv6 addresses or hostnames with and without their
+// trailing dot.
+func suppressEquivalentRecordValues(k, old, new string, d *schema.ResourceData) bool {
+	o, n := d.GetChange("values")
+	zone := d.Get("zone").(string)
+	recordType := strings.ToUpper(d.Get("type").(string))
+	return areStringSlicesEqual(
+		canonicalRecordValues(zone, recordType, expandArray(o.(*schema.Set).List())),
+		canonicalRecordValues(zone, recordType, expandArray(n.(*schema.Set).List())),
+	)
+}
+
+// knownRecordValues returns the values of a record which are known at
+// plan time. Once read with Get, the unknown elements of a set can't be
+// told apart from the other values, so they are skipped from the raw
+// configuration when the values are written there.
+func knownRecordValues(d *schema.ResourceDiff) []string {
+	config := d.GetRawConfig()
+	if config.IsKnown() && !config.IsNull() {
+		if v := config.GetAttr("values"); !v.IsNull() && !v.IsWhollyKnown() {
+			values := []string{}
+			if !v.IsKnown() {
+				return values
+			}
+			for it := v.ElementIterator(); it.Next(); {
+				_, e := it.Element()
+				if e.IsKnown() && !e.IsNull() {
+					values = append(values, e.AsString())
+				}
+			}
+			return values
+		}
+	}
+	return expandArray(d.Get("values").(*schema.Set).List())
+}
+
+// resourceLiveDNSRecordCustomizeDiff computes the values of a record
+// from its blocks and validates them according to its type.
+func resourceLiveDNSRecordCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := customizeRecordBlocksDiff(d); err != nil {
+		return err
+	}
+	if !d.NewValueKnown("type") || !d.NewValueKnown("values") || !d.NewValueKnown("zone") {
+		return nil
+	}
+	zone := d.Get("zone").(string)
+	recordType := strings.ToUpper(d.Get("type").(string))
+	values := expandArray(d.Get("values").(*schema.Set).List())
+	for _, t := range singleValueRecordTypes {
+		if recordType == t && len(values) > 1 {
+			return fmt.Errorf("%s records can't have more than one value", recordType)
+		}
+	}
+	for _, v := range knownRecordValues(d) {
+		if _, err := canonicalRecordValue(zone, recordType, v); err != nil {
+			return fmt.Errorf("invalid %s record value %q: %w", recordType, v, err)
+		}
+	}
+	return nil
+}
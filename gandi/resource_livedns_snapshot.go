@@ -0,0 +1,142 @@
+package gandi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-gandi/go-gandi/livedns"
+	"github.com/go-gandi/go-gandi/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceLiveDNSSnapshot() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceLiveDNSSnapshotCreate,
+		ReadContext:   resourceLiveDNSSnapshotRead,
+		DeleteContext: resourceLiveDNSSnapshotDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"zone": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The FQDN of the domain",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The name of the snapshot",
+			},
+			"snapshot_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ID of the snapshot",
+			},
+			"automatic": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the snapshot was taken automatically",
+			},
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The creation date of the snapshot",
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{Default: schema.DefaultTimeout(1 * time.Minute)},
+	}
+}
+
+func expandSnapshotID(id string) (zone, snapshotID string, err error) {
+	splitID := strings.Split(id, "/")
+	if len(splitID) != 2 {
+		err = errors.New("id format should be '{zone}/{snapshot_id}'")
+		return
+	}
+	return splitID[0], splitID[1], nil
+}
+
+func resourceLiveDNSSnapshotCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*clients).API
+	zone := d.Get("zone").(string)
+
+	// go-gandi doesn't allow to name snapshots
+	request := struct {
+		Name string `json:"name,omitempty"`
+	}{Name: d.Get("name").(string)}
+	var response struct {
+		ID string `json:"id"`
+	}
+	if err := client.Post(ctx, "livedns/domains/"+zone+"/snapshots", request, &response); err != nil {
+		return diagFromRequestError(fmt.Errorf("failed to create a snapshot of zone %s: %w", zone, err), resourceLiveDNSSnapshot().Schema, nil)
+	}
+	if response.ID == "" {
+		return diag.Errorf("the snapshot of zone %s has been created but its ID is missing from the response", zone)
+	}
+	d.SetId(zone + "/" + response.ID)
+	return resourceLiveDNSSnapshotRead(ctx, d, meta)
+}
+
+func resourceLiveDNSSnapshotRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
+	zone, snapshotID, err := expandSnapshotID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var snapshot livedns.Snapshot
+	err = c.call(ctx, func() (err error) {
+		snapshot, err = c.LiveDNS.GetSnapshot(zone, snapshotID)
+		return
+	})
+	if err != nil {
+		requestError, ok := err.(*types.RequestError)
+		if ok && requestError.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	if err = d.Set("zone", zone); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set zone for %s: %w", d.Id(), err))
+	}
+	if err = d.Set("snapshot_id", snapshot.ID); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set snapshot_id for %s: %w", d.Id(), err))
+	}
+	if err = d.Set("name", snapshot.Name); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set name for %s: %w", d.Id(), err))
+	}
+	if err = d.Set("automatic", snapshot.Automatic != nil && *snapshot.Automatic); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set automatic for %s: %w", d.Id(), err))
+	}
+	if err = d.Set("created_at", snapshot.CreatedAt.Format(time.RFC3339)); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set created_at for %s: %w", d.Id(), err))
+	}
+	return nil
+}
+
+func resourceLiveDNSSnapshotDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
+	zone, snapshotID, err := expandSnapshotID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = c.call(ctx, func() error { return c.LiveDNS.DeleteSnapshot(zone, snapshotID) })
+	if err != nil {
+		requestError, ok := err.(*types.RequestError)
+		if !ok || requestError.StatusCode != 404 {
+			return diag.FromErr(fmt.Errorf("failed to delete the snapshot %s of zone %s: %w", snapshotID, zone, err))
+		}
+	}
+	d.SetId("")
+	return nil
+}
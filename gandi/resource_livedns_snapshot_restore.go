@@ -0,0 +1,97 @@
+package gandi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-gandi/go-gandi/livedns"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceLiveDNSSnapshotRestore restores the records of a zone from a
+// snapshot when it is created. It doesn't track the records afterwards:
+// destroying it only removes it from the state.
+func resourceLiveDNSSnapshotRestore() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceLiveDNSSnapshotRestoreCreate,
+		ReadContext:   resourceLiveDNSSnapshotRestoreRead,
+		DeleteContext: resourceLiveDNSSnapshotRestoreDelete,
+
+		Schema: map[string]*schema.Schema{
+			"zone": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The FQDN of the domain",
+			},
+			"snapshot_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the snapshot to restore",
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Arbitrary values which restore the snapshot again when they change",
+			},
+			"restored_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The date the snapshot was restored",
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{Default: schema.DefaultTimeout(1 * time.Minute)},
+	}
+}
+
+func resourceLiveDNSSnapshotRestoreCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
+	zone := d.Get("zone").(string)
+	snapshotID := d.Get("snapshot_id").(string)
+
+	var snapshot livedns.Snapshot
+	err := c.call(ctx, func() (err error) {
+		snapshot, err = c.LiveDNS.GetSnapshot(zone, snapshotID)
+		return
+	})
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to get the snapshot %s of zone %s: %w", snapshotID, zone, err))
+	}
+	// The SOA record is managed by LiveDNS
+	records := []livedns.DomainRecord{}
+	for _, r := range snapshot.ZoneData {
+		if r.RrsetType == "SOA" {
+			continue
+		}
+		r.RrsetHref = ""
+		records = append(records, r)
+	}
+	c.Cache.invalidateZone(zone)
+	err = c.call(ctx, func() error {
+		_, err := c.LiveDNS.UpdateDomainRecords(zone, records)
+		return err
+	})
+	if err != nil {
+		return diagFromRequestError(fmt.Errorf("failed to restore the snapshot %s of zone %s: %w", snapshotID, zone, err), nil, nil)
+	}
+
+	d.SetId(zone + "/" + snapshotID)
+	if err = d.Set("restored_at", time.Now().UTC().Format(time.RFC3339)); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set restored_at for %s: %w", d.Id(), err))
+	}
+	return nil
+}
+
+func resourceLiveDNSSnapshotRestoreRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return nil
+}
+
+func resourceLiveDNSSnapshotRestoreDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,81 @@
+package gandi
+
+import (
+	"testing"
+
+	"github.com/go-gandi/go-gandi/livedns"
+)
+
+func TestOfflineSnapshot_basic(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addZone("example.com", livedns.DomainRecord{RrsetName: "www", RrsetType: "A", RrsetTTL: 300, RrsetValues: []string{"192.168.0.1"}})
+	p := newTestOfflineProvider(t, api)
+
+	state := p.apply("gandi_livedns_snapshot", nil, map[string]interface{}{
+		"zone": "example.com",
+		"name": "before-migration",
+	})
+	testCheckAttributes(t, state, map[string]string{
+		"zone":      "example.com",
+		"name":      "before-migration",
+		"automatic": "false",
+	})
+	snapshotID := state.Attributes["snapshot_id"]
+	if state.ID != "example.com/"+snapshotID || state.Attributes["created_at"] == "" {
+		t.Fatalf("unexpected snapshot state: %v", state.Attributes)
+	}
+
+	imported := p.importState("gandi_livedns_snapshot", state.ID)
+	testCheckAttributes(t, imported, map[string]string{
+		"name":        "before-migration",
+		"snapshot_id": snapshotID,
+	})
+
+	// A bad change is rolled back by restoring the snapshot
+	api.setRecord("example.com", livedns.DomainRecord{RrsetName: "www", RrsetType: "A", RrsetTTL: 300, RrsetValues: []string{"10.0.0.1"}})
+	api.setRecord("example.com", livedns.DomainRecord{RrsetName: "bad", RrsetType: "A", RrsetTTL: 300, RrsetValues: []string{"10.0.0.2"}})
+
+	reads := api.requestCount("GET livedns/domains/example.com/snapshots/")
+	data := p.readDataSource("gandi_livedns_snapshots", map[string]interface{}{"zone": "example.com"})
+	testCheckAttributes(t, data, map[string]string{
+		"snapshots.#":           "1",
+		"snapshots.0.id":        snapshotID,
+		"snapshots.0.name":      "before-migration",
+		"snapshots.0.automatic": "false",
+		"snapshots.0.records.#": "0",
+	})
+	if n := api.requestCount("GET livedns/domains/example.com/snapshots/") - reads; n != 0 {
+		t.Fatalf("the snapshots shouldn't be read one by one unless their records are included, got %d requests", n)
+	}
+
+	data = p.readDataSource("gandi_livedns_snapshots", map[string]interface{}{"zone": "example.com", "include_records": true})
+	testCheckAttributes(t, data, map[string]string{
+		"snapshots.#":                    "1",
+		"snapshots.0.id":                 snapshotID,
+		"snapshots.0.name":               "before-migration",
+		"snapshots.0.records.#":          "1",
+		"snapshots.0.records.0.name":     "www",
+		"snapshots.0.records.0.values.0": "192.168.0.1",
+		"snapshots.0.zonefile":           "$ORIGIN example.com.\nwww 300 IN A 192.168.0.1\n",
+	})
+
+	restore := p.apply("gandi_livedns_snapshot_restore", nil, map[string]interface{}{
+		"zone":        "example.com",
+		"snapshot_id": snapshotID,
+	})
+	if restore.Attributes["restored_at"] == "" {
+		t.Fatalf("the restore date should be set")
+	}
+	if values := api.record("example.com", "www", "A"); !areStringSlicesEqual(values, []string{"192.168.0.1"}) {
+		t.Fatalf("the www record should have been restored, got %v", values)
+	}
+	if api.record("example.com", "bad", "A") != nil {
+		t.Fatalf("the bad record should have been removed")
+	}
+	p.destroy("gandi_livedns_snapshot_restore", restore)
+
+	p.destroy("gandi_livedns_snapshot", state)
+	if state = p.refresh("gandi_livedns_snapshot", state); state != nil {
+		t.Fatalf("the snapshot should have been deleted")
+	}
+}
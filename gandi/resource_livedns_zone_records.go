@@ -0,0 +1,381 @@
+package gandi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-gandi/go-gandi/livedns"
+	"github.com/go-gandi/go-gandi/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceLiveDNSZoneRecords() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceLiveDNSZoneRecordsCreate,
+		ReadContext:   resourceLiveDNSZoneRecordsRead,
+		UpdateContext: resourceLiveDNSZoneRecordsUpdate,
+		DeleteContext: resourceLiveDNSZoneRecordsDelete,
+		CustomizeDiff: resourceLiveDNSZoneRecordsCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceLiveDNSZoneRecordsImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"zone": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The FQDN of the domain",
+			},
+			"record": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "The records of the zone, each with a distinct name and type. Records of the zone not listed here are removed.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The name of the record",
+						},
+						"type": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateRecordType,
+							Description:  "The type of the record",
+						},
+						"ttl": {
+							Type:        schema.TypeInt,
+							Required:    true,
+							Description: "The TTL of the record",
+						},
+						"values": {
+							Type:        schema.TypeSet,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Required:    true,
+							Description: "A list of values of the record",
+						},
+					},
+				},
+			},
+			"ignore_default_records": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Ignore the records managed by Gandi (the apex NS and SOA records)",
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{Default: schema.DefaultTimeout(1 * time.Minute)},
+	}
+}
+
+// isDefaultRecord returns true if the record is managed by Gandi
+func isDefaultRecord(record livedns.DomainRecord) bool {
+	return record.RrsetName == "@" && (record.RrsetType == "NS" || record.RrsetType == "SOA")
+}
+
+// zoneRecordKey identifies a record of a zone. Since names and types
+// are case insensitive, the name is lower-cased and the type
+// upper-cased.
+func zoneRecordKey(record livedns.DomainRecord) string {
+	return strings.ToLower(record.RrsetName) + "/" + strings.ToUpper(record.RrsetType)
+}
+
+// recordValuesEqual compares two lists of values of a record of zone
+// by their canonical form, as the values of gandi_livedns_record are,
+// since LiveDNS returns the values it is sent in its own form.
+func recordValuesEqual(zone, recordType string, a, b []string) bool {
+	recordType = strings.ToUpper(recordType)
+	return areStringSlicesEqual(canonicalRecordValues(zone, recordType, a), canonicalRecordValues(zone, recordType, b))
+}
+
+// diffZoneRecords computes the minimal set of records to create,
+// update and delete to turn the current records into the desired ones.
+func diffZoneRecords(zone string, current, desired []livedns.DomainRecord) (toCreate, toUpdate, toDelete []livedns.DomainRecord) {
+	currentByKey := make(map[string]livedns.DomainRecord)
+	for _, r := range current {
+		currentByKey[zoneRecordKey(r)] = r
+	}
+	desiredKeys := make(map[string]bool)
+	for _, r := range desired {
+		desiredKeys[zoneRecordKey(r)] = true
+		existing, ok := currentByKey[zoneRecordKey(r)]
+		if !ok {
+			r.RrsetType = strings.ToUpper(r.RrsetType)
+			toCreate = append(toCreate, r)
+		} else if existing.RrsetTTL != r.RrsetTTL || !recordValuesEqual(zone, existing.RrsetType, existing.RrsetValues, r.RrsetValues) {
+			r.RrsetName, r.RrsetType = existing.RrsetName, existing.RrsetType
+			toUpdate = append(toUpdate, r)
+		}
+	}
+	for _, r := range current {
+		if !desiredKeys[zoneRecordKey(r)] {
+			toDelete = append(toDelete, r)
+		}
+	}
+	return
+}
+
+func expandZoneRecords(records *schema.Set) (expanded []livedns.DomainRecord) {
+	for _, r := range records.List() {
+		record := r.(map[string]interface{})
+		expanded = append(expanded, livedns.DomainRecord{
+			RrsetName:   record["name"].(string),
+			RrsetType:   record["type"].(string),
+			RrsetTTL:    record["ttl"].(int),
+			RrsetValues: expandArray(record["values"].(*schema.Set).List()),
+		})
+	}
+	return
+}
+
+// flattenZoneRecords converts the API records to the record blocks.
+// The names and types of the state are kept when they only differ by
+// their case, as are the values when they are equivalent to the state
+// ones, to avoid spurious diffs.
+func flattenZoneRecords(zone string, records, state []livedns.DomainRecord) []interface{} {
+	stateByKey := make(map[string]livedns.DomainRecord)
+	for _, r := range state {
+		stateByKey[zoneRecordKey(r)] = r
+	}
+	flattened := make([]interface{}, 0, len(records))
+	for _, r := range records {
+		name, recordType, values := r.RrsetName, r.RrsetType, r.RrsetValues
+		if s, ok := stateByKey[zoneRecordKey(r)]; ok {
+			name, recordType = s.RrsetName, s.RrsetType
+			if recordValuesEqual(zone, r.RrsetType, s.RrsetValues, values) {
+				values = s.RrsetValues
+			}
+		}
+		flattened = append(flattened, map[string]interface{}{
+			"name":   name,
+			"type":   recordType,
+			"ttl":    r.RrsetTTL,
+			"values": values,
+		})
+	}
+	return flattened
+}
+
+// knownZoneRecords returns the records of the plan whose name and type
+// are known. Once read with Get, the unknown attributes of a set can't
+// be told apart from the other values, so the raw configuration is
+// used when it is available.
+func knownZoneRecords(d *schema.ResourceDiff) (records []livedns.DomainRecord) {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() {
+		return expandZoneRecords(d.Get("record").(*schema.Set))
+	}
+	blocks := config.GetAttr("record")
+	if blocks.IsNull() || !blocks.IsKnown() {
+		return nil
+	}
+	for it := blocks.ElementIterator(); it.Next(); {
+		_, block := it.Element()
+		if !block.IsKnown() || block.IsNull() {
+			continue
+		}
+		name, recordType := block.GetAttr("name"), block.GetAttr("type")
+		if !name.IsKnown() || name.IsNull() || !recordType.IsKnown() || recordType.IsNull() {
+			continue
+		}
+		records = append(records, livedns.DomainRecord{RrsetName: name.AsString(), RrsetType: recordType.AsString()})
+	}
+	return
+}
+
+// resourceLiveDNSZoneRecordsCustomizeDiff rejects the records having
+// the same name and type, since LiveDNS stores them as a single record.
+func resourceLiveDNSZoneRecordsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	seen := make(map[string]bool)
+	for _, r := range knownZoneRecords(d) {
+		key := zoneRecordKey(r)
+		if seen[key] {
+			return fmt.Errorf("the record %s %s is declared more than once: its values should be merged in a single record block", r.RrsetName, r.RrsetType)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// getZoneRecords returns the records of a zone, sorted by name and
+// type, without the Gandi default records if ignoreDefaults is true.
+// Long TXT values are joined.
+func getZoneRecords(ctx context.Context, cache *readCache, zone string, ignoreDefaults bool) ([]livedns.DomainRecord, error) {
+	records, err := cache.zoneRecords(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+	var filtered []livedns.DomainRecord
+	for _, r := range records {
+		if ignoreDefaults && isDefaultRecord(r) {
+			continue
+		}
+		r.RrsetValues = joinTXTValues(r.RrsetType, r.RrsetValues)
+		filtered = append(filtered, r)
+	}
+	sort.Slice(filtered, func(i, j int) bool {
+		return zoneRecordKey(filtered[i]) < zoneRecordKey(filtered[j])
+	})
+	return filtered, nil
+}
+
+// applyZoneRecords writes the changes between the current and the
+// desired records of a zone. It stops before the next write once the
+// context is done.
+func applyZoneRecords(ctx context.Context, c *clients, zone string, current, desired []livedns.DomainRecord) error {
+	toCreate, toUpdate, toDelete := diffZoneRecords(zone, current, desired)
+	aborted := func() error {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("failed to apply the records of zone %s: %w", zone, err)
+		}
+		return nil
+	}
+	for _, r := range toDelete {
+		if err := aborted(); err != nil {
+			return err
+		}
+		r := r
+		err := c.call(ctx, func() error {
+			return c.LiveDNS.DeleteDomainRecord(zone, r.RrsetName, r.RrsetType)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete the record %s %s of zone %s: %w", r.RrsetName, r.RrsetType, zone, err)
+		}
+	}
+	for _, r := range toUpdate {
+		if err := aborted(); err != nil {
+			return err
+		}
+		r := r
+		err := c.call(ctx, func() error {
+			_, err := c.LiveDNS.UpdateDomainRecordByNameAndType(zone, r.RrsetName, r.RrsetType, r.RrsetTTL, splitTXTValues(r.RrsetType, r.RrsetValues))
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update the record %s %s of zone %s: %w", r.RrsetName, r.RrsetType, zone, err)
+		}
+	}
+	for _, r := range toCreate {
+		if err := aborted(); err != nil {
+			return err
+		}
+		r := r
+		err := c.callNonIdempotent(ctx, func() error {
+			_, err := c.LiveDNS.CreateDomainRecord(zone, r.RrsetName, r.RrsetType, r.RrsetTTL, splitTXTValues(r.RrsetType, r.RrsetValues))
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create the record %s %s of zone %s: %w", r.RrsetName, r.RrsetType, zone, err)
+		}
+	}
+	return nil
+}
+
+// zoneRecordsIgnoreDefaults returns whether the Gandi default records
+// are ignored. A failed creation only keeps the ID in the state, in
+// which case the default of ignore_default_records applies, so that
+// the default records aren't removed when the zone is destroyed.
+func zoneRecordsIgnoreDefaults(d *schema.ResourceData) bool {
+	state := d.GetRawState()
+	if !state.IsNull() && state.GetAttr("ignore_default_records").IsNull() {
+		return true
+	}
+	return d.Get("ignore_default_records").(bool)
+}
+
+func resourceLiveDNSZoneRecordsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zone := d.Get("zone").(string)
+	ignoreDefaults := d.Get("ignore_default_records").(bool)
+
+	meta.(*clients).Cache.invalidateZone(zone)
+	current, err := getZoneRecords(ctx, meta.(*clients).Cache, zone, ignoreDefaults)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	desired := expandZoneRecords(d.Get("record").(*schema.Set))
+	// The zone is tracked as soon as the first record is written, so
+	// that a failed apply is resumed by the next one.
+	d.SetId(zone)
+	d.Partial(true)
+	if err = applyZoneRecords(ctx, meta.(*clients), zone, current, desired); err != nil {
+		return diagFromRequestError(err, nil, nil)
+	}
+	d.Partial(false)
+	return resourceLiveDNSZoneRecordsRead(ctx, d, meta)
+}
+
+func resourceLiveDNSZoneRecordsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zone := d.Id()
+	ignoreDefaults := zoneRecordsIgnoreDefaults(d)
+
+	records, err := getZoneRecords(ctx, meta.(*clients).Cache, zone, ignoreDefaults)
+	if err != nil {
+		requestError, ok := err.(*types.RequestError)
+		if ok && requestError.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	if err = d.Set("zone", zone); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set zone for %s: %w", d.Id(), err))
+	}
+	state := expandZoneRecords(d.Get("record").(*schema.Set))
+	if err = d.Set("record", flattenZoneRecords(zone, records, state)); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set records for %s: %w", d.Id(), err))
+	}
+	if err = d.Set("ignore_default_records", ignoreDefaults); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set ignore_default_records for %s: %w", d.Id(), err))
+	}
+	return nil
+}
+
+func resourceLiveDNSZoneRecordsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zone := d.Id()
+	ignoreDefaults := d.Get("ignore_default_records").(bool)
+
+	meta.(*clients).Cache.invalidateZone(zone)
+	current, err := getZoneRecords(ctx, meta.(*clients).Cache, zone, ignoreDefaults)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	desired := expandZoneRecords(d.Get("record").(*schema.Set))
+	d.Partial(true)
+	if err = applyZoneRecords(ctx, meta.(*clients), zone, current, desired); err != nil {
+		return diagFromRequestError(err, nil, nil)
+	}
+	d.Partial(false)
+	return resourceLiveDNSZoneRecordsRead(ctx, d, meta)
+}
+
+// resourceLiveDNSZoneRecordsDelete removes all the records of the
+// zone, except the Gandi default ones if they are ignored.
+func resourceLiveDNSZoneRecordsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zone := d.Id()
+	ignoreDefaults := zoneRecordsIgnoreDefaults(d)
+
+	meta.(*clients).Cache.invalidateZone(zone)
+	current, err := getZoneRecords(ctx, meta.(*clients).Cache, zone, ignoreDefaults)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err = applyZoneRecords(ctx, meta.(*clients), zone, current, nil); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId("")
+	return nil
+}
+
+// resourceLiveDNSZoneRecordsImport imports the records of a zone. The
+// Gandi default records are ignored, as they are by default.
+func resourceLiveDNSZoneRecordsImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if err := d.Set("ignore_default_records", true); err != nil {
+		return nil, fmt.Errorf("failed to set ignore_default_records for %s: %w", d.Id(), err)
+	}
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,212 @@
+package gandi
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-gandi/go-gandi/livedns"
+	"github.com/hashicorp/go-cty/cty"
+)
+
+func TestDiffZoneRecords(t *testing.T) {
+	current := []livedns.DomainRecord{
+		{RrsetName: "www", RrsetType: "A", RrsetTTL: 300, RrsetValues: []string{"192.168.0.1"}},
+		{RrsetName: "old", RrsetType: "A", RrsetTTL: 300, RrsetValues: []string{"192.168.0.2"}},
+		{RrsetName: "@", RrsetType: "TXT", RrsetTTL: 300, RrsetValues: []string{"\"v=spf1 -all\""}},
+		{RrsetName: "mail", RrsetType: "CNAME", RrsetTTL: 300, RrsetValues: []string{"mx.example.net."}},
+		{RrsetName: "ftp", RrsetType: "CNAME", RrsetTTL: 300, RrsetValues: []string{"files.example.com."}},
+		{RrsetName: "v6", RrsetType: "AAAA", RrsetTTL: 300, RrsetValues: []string{"2001:db8::1"}},
+		{RrsetName: "@", RrsetType: "MX", RrsetTTL: 300, RrsetValues: []string{"10 mail.example.com."}},
+	}
+	desired := []livedns.DomainRecord{
+		// Equivalent to the current values once canonicalized
+		{RrsetName: "ftp", RrsetType: "CNAME", RrsetTTL: 300, RrsetValues: []string{"Files"}},
+		{RrsetName: "v6", RrsetType: "AAAA", RrsetTTL: 300, RrsetValues: []string{"2001:DB8:0:0::1"}},
+		{RrsetName: "@", RrsetType: "MX", RrsetTTL: 300, RrsetValues: []string{"10  mail"}},
+		{RrsetName: "www", RrsetType: "A", RrsetTTL: 300, RrsetValues: []string{"192.168.0.1"}},
+		{RrsetName: "@", RrsetType: "TXT", RrsetTTL: 300, RrsetValues: []string{"v=spf1 -all"}},
+		{RrsetName: "mail", RrsetType: "CNAME", RrsetTTL: 3600, RrsetValues: []string{"mx.example.net."}},
+		{RrsetName: "new", RrsetType: "AAAA", RrsetTTL: 300, RrsetValues: []string{"::1"}},
+	}
+
+	toCreate, toUpdate, toDelete := diffZoneRecords("example.com", current, desired)
+	if len(toCreate) != 1 || zoneRecordKey(toCreate[0]) != "new/AAAA" {
+		t.Errorf("only new/AAAA should be created, got %v", toCreate)
+	}
+	if len(toUpdate) != 1 || zoneRecordKey(toUpdate[0]) != "mail/CNAME" {
+		t.Errorf("only mail/CNAME should be updated, got %v", toUpdate)
+	}
+	if len(toDelete) != 1 || zoneRecordKey(toDelete[0]) != "old/A" {
+		t.Errorf("only old/A should be deleted, got %v", toDelete)
+	}
+}
+
+func testOfflineZoneRecordsConfig(records ...interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"zone":   "example.com",
+		"record": records,
+	}
+}
+
+func testOfflineZoneRecord(name, recordType string, ttl int, values ...interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"name":   name,
+		"type":   recordType,
+		"ttl":    ttl,
+		"values": values,
+	}
+}
+
+func TestOfflineZoneRecords_basic(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addZone("example.com",
+		livedns.DomainRecord{RrsetName: "@", RrsetType: "NS", RrsetValues: fakeLiveDNSNameservers},
+		livedns.DomainRecord{RrsetName: "stray", RrsetType: "A", RrsetValues: []string{"10.0.0.1"}},
+	)
+	p := newTestOfflineProvider(t, api)
+	config := testOfflineZoneRecordsConfig(
+		testOfflineZoneRecord("www", "A", 300, "192.168.0.1", "192.168.0.2"),
+		testOfflineZoneRecord("@", "TXT", 300, "v=spf1 -all"),
+	)
+
+	state := p.apply("gandi_livedns_zone_records", nil, config)
+	testCheckAttributes(t, state, map[string]string{
+		"id":       "example.com",
+		"record.#": "2",
+	})
+	if api.record("example.com", "stray", "A") != nil {
+		t.Fatalf("the stray record should have been removed")
+	}
+	if api.record("example.com", "@", "NS") == nil {
+		t.Fatalf("the default NS record should have been kept")
+	}
+	p.planEmpty("gandi_livedns_zone_records", state, config)
+
+	// A record added by hand is removed on the next apply
+	api.setRecord("example.com", livedns.DomainRecord{RrsetName: "stray", RrsetType: "A", RrsetValues: []string{"10.0.0.1"}})
+	state = p.refresh("gandi_livedns_zone_records", state)
+	testCheckAttributes(t, state, map[string]string{"record.#": "3"})
+	state = p.apply("gandi_livedns_zone_records", state, config)
+	if api.record("example.com", "stray", "A") != nil {
+		t.Fatalf("the stray record should have been removed")
+	}
+
+	config = testOfflineZoneRecordsConfig(
+		testOfflineZoneRecord("www", "A", 600, "192.168.0.1"),
+	)
+	writes := func() int {
+		return api.requestCount("PUT") + api.requestCount("DELETE") + api.requestCount("POST")
+	}
+	before := writes()
+	state = p.apply("gandi_livedns_zone_records", state, config)
+	if values := api.record("example.com", "www", "A"); !areStringSlicesEqual(values, []string{"192.168.0.1"}) {
+		t.Fatalf("the www record should have been updated, got %v", values)
+	}
+	if api.record("example.com", "@", "TXT") != nil {
+		t.Fatalf("the TXT record should have been removed")
+	}
+	// Only the www update and the TXT removal are sent
+	if n := writes() - before; n != 2 {
+		t.Fatalf("expected 2 write requests, got %d", n)
+	}
+
+	imported := p.importState("gandi_livedns_zone_records", "example.com")
+	testCheckAttributes(t, imported, map[string]string{
+		"zone":                   "example.com",
+		"ignore_default_records": "true",
+		"record.#":               "1",
+	})
+
+	p.destroy("gandi_livedns_zone_records", state)
+	if records := api.zoneRecords("example.com"); len(records) != 1 || records[0].RrsetType != "NS" {
+		t.Fatalf("only the default NS record should remain, got %v", records)
+	}
+}
+
+func TestOfflineZoneRecords_case(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addZone("example.com",
+		livedns.DomainRecord{RrsetName: "www", RrsetType: "A", RrsetTTL: 300, RrsetValues: []string{"192.168.0.1"}},
+	)
+	p := newTestOfflineProvider(t, api)
+	config := testOfflineZoneRecordsConfig(
+		testOfflineZoneRecord("WWW", "a", 300, "192.168.0.1"),
+		testOfflineZoneRecord("Mail", "mx", 300, "10 mx.example.net."),
+	)
+
+	state := p.apply("gandi_livedns_zone_records", nil, config)
+	if n := api.requestCount("DELETE") + api.requestCount("PUT"); n != 0 {
+		t.Fatalf("the existing record should have been matched whatever its case, got %d writes", n)
+	}
+	if api.record("example.com", "Mail", "MX") == nil {
+		t.Fatalf("the MX record should have been created with an upper case type")
+	}
+	p.planEmpty("gandi_livedns_zone_records", p.refresh("gandi_livedns_zone_records", state), config)
+
+	for _, config := range []map[string]interface{}{
+		testOfflineZoneRecordsConfig(testOfflineZoneRecord("www", "AXFR", 300, "192.168.0.1")),
+		testOfflineZoneRecordsConfig(
+			testOfflineZoneRecord("www", "A", 300, "192.168.0.1"),
+			testOfflineZoneRecord("WWW", "a", 300, "192.168.0.2"),
+		),
+	} {
+		if _, diags := p.plan("gandi_livedns_zone_records", state, config); !diags.HasError() {
+			t.Errorf("expected the plan of %v to fail", config)
+		}
+	}
+}
+
+// TestOfflineZoneRecords_failedCreate checks a failed creation is
+// tracked and resumed by the next apply.
+func TestOfflineZoneRecords_failedCreate(t *testing.T) {
+	withRetryBaseDelay(t, time.Millisecond)
+	api := newFakeGandiAPI(t)
+	api.addZone("example.com",
+		livedns.DomainRecord{RrsetName: "@", RrsetType: "NS", RrsetValues: fakeLiveDNSNameservers},
+	)
+	p := newTestOfflineProvider(t, api)
+	config := testOfflineZoneRecordsConfig(
+		testOfflineZoneRecord("www", "A", 300, "192.168.0.1"),
+		testOfflineZoneRecord("api", "A", 300, "192.168.0.2"),
+	)
+
+	// Only the first record can be created
+	posts := 0
+	api.beforeRequest = func(r *http.Request) {
+		if r.Method == http.MethodPost {
+			if posts++; posts > 1 {
+				api.throttled = 1
+			}
+		}
+	}
+	state, diags := p.applyWithDiags("gandi_livedns_zone_records", nil, config)
+	if !diags.HasError() {
+		t.Fatalf("the creation should have failed")
+	}
+	if state == nil || state.ID != "example.com" {
+		t.Fatalf("the zone should be tracked once a record is written, got %v", state)
+	}
+	if n := state.Attributes["record.#"]; n != "" && n != "0" {
+		t.Fatalf("the records which may not be written shouldn't be in the state, got %s", n)
+	}
+
+	// Destroying the failed creation keeps the default records, since
+	// only the ID is in its state
+	failed := state.DeepCopy()
+	failed.RawState = cty.ObjectVal(map[string]cty.Value{"ignore_default_records": cty.NullVal(cty.Bool)})
+	p.destroy("gandi_livedns_zone_records", failed)
+	if api.record("example.com", "@", "NS") == nil {
+		t.Fatalf("the default NS record should have been kept")
+	}
+
+	api.beforeRequest = nil
+	state = p.apply("gandi_livedns_zone_records", p.refresh("gandi_livedns_zone_records", state), config)
+	if api.record("example.com", "www", "A") == nil || api.record("example.com", "api", "A") == nil {
+		t.Fatalf("the records should have been created by the next apply")
+	}
+	if api.record("example.com", "@", "NS") == nil {
+		t.Fatalf("the default NS record should have been kept")
+	}
+	p.planEmpty("gandi_livedns_zone_records", state, config)
+}
@@ -0,0 +1,128 @@
+package gandi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-gandi/go-gandi/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceLiveDNSZoneFile() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceLiveDNSZoneFileCreate,
+		ReadContext:   resourceLiveDNSZoneFileRead,
+		UpdateContext: resourceLiveDNSZoneFileUpdate,
+		DeleteContext: resourceLiveDNSZoneFileDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: resourceLiveDNSZoneFileCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"zone": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The FQDN of the domain",
+			},
+			"content": {
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressEquivalentZoneFile,
+				Description:      "The content of the zone file. Records of the zone not listed here are removed. The SOA and apex NS records are managed by Gandi and ignored.",
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{Default: schema.DefaultTimeout(1 * time.Minute)},
+	}
+}
+
+// suppressEquivalentZoneFile suppresses the diff when the configured
+// zone file describes the same records as the one in the state, which
+// is always in its normalized form.
+func suppressEquivalentZoneFile(k, old, new string, d *schema.ResourceData) bool {
+	normalized, err := normalizeZoneFile(d.Get("zone").(string), new)
+	return err == nil && normalized == old
+}
+
+// resourceLiveDNSZoneFileCustomizeDiff reports zone file syntax errors
+// at plan time.
+func resourceLiveDNSZoneFileCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("content") || !d.NewValueKnown("zone") {
+		return nil
+	}
+	if _, err := parseZoneFile(d.Get("zone").(string), d.Get("content").(string)); err != nil {
+		return fmt.Errorf("invalid zone file: %w", err)
+	}
+	return nil
+}
+
+func resourceLiveDNSZoneFileApply(ctx context.Context, d *schema.ResourceData, meta interface{}, zone string) error {
+	desired, err := parseZoneFile(zone, d.Get("content").(string))
+	if err != nil {
+		return fmt.Errorf("invalid zone file: %w", err)
+	}
+	meta.(*clients).Cache.invalidateZone(zone)
+	current, err := getZoneRecords(ctx, meta.(*clients).Cache, zone, true)
+	if err != nil {
+		return err
+	}
+	return applyZoneRecords(ctx, meta.(*clients), zone, withoutZoneFileManagedRecords(current), withoutZoneFileManagedRecords(desired))
+}
+
+func resourceLiveDNSZoneFileCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zone := d.Get("zone").(string)
+	if err := resourceLiveDNSZoneFileApply(ctx, d, meta, zone); err != nil {
+		return diagFromRequestError(err, nil, nil)
+	}
+	d.SetId(zone)
+	return resourceLiveDNSZoneFileRead(ctx, d, meta)
+}
+
+func resourceLiveDNSZoneFileRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zone := d.Id()
+
+	records, err := getZoneRecords(ctx, meta.(*clients).Cache, zone, true)
+	if err != nil {
+		requestError, ok := err.(*types.RequestError)
+		if ok && requestError.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	if err = d.Set("zone", zone); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set zone for %s: %w", d.Id(), err))
+	}
+	content := renderZoneFile(zone, withoutZoneFileManagedRecords(records))
+	if err = d.Set("content", content); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set content for %s: %w", d.Id(), err))
+	}
+	return nil
+}
+
+func resourceLiveDNSZoneFileUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := resourceLiveDNSZoneFileApply(ctx, d, meta, d.Id()); err != nil {
+		return diagFromRequestError(err, nil, nil)
+	}
+	return resourceLiveDNSZoneFileRead(ctx, d, meta)
+}
+
+// resourceLiveDNSZoneFileDelete removes all the records of the zone,
+// except the ones managed by Gandi.
+func resourceLiveDNSZoneFileDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zone := d.Id()
+
+	meta.(*clients).Cache.invalidateZone(zone)
+	current, err := getZoneRecords(ctx, meta.(*clients).Cache, zone, true)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err = applyZoneRecords(ctx, meta.(*clients), zone, withoutZoneFileManagedRecords(current), nil); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,85 @@
+package gandi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-gandi/go-gandi/livedns"
+)
+
+func TestOfflineZoneFile_basic(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addZone("example.com",
+		livedns.DomainRecord{RrsetName: "@", RrsetType: "NS", RrsetValues: fakeLiveDNSNameservers},
+		livedns.DomainRecord{RrsetName: "stray", RrsetType: "A", RrsetValues: []string{"10.0.0.1"}},
+	)
+	p := newTestOfflineProvider(t, api)
+	config := map[string]interface{}{
+		"zone": "example.com",
+		"content": `$TTL 300
+@	IN NS ns1.example.net.
+@	IN TXT "v=spf1 -all"
+www	IN A 192.168.0.1
+	IN A 192.168.0.2
+`,
+	}
+
+	state := p.apply("gandi_livedns_zonefile", nil, config)
+	testCheckAttributes(t, state, map[string]string{
+		"id": "example.com",
+		"content": `$ORIGIN example.com.
+@ 300 IN TXT "v=spf1 -all"
+www 300 IN A 192.168.0.1
+www 300 IN A 192.168.0.2
+`,
+	})
+	if api.record("example.com", "stray", "A") != nil {
+		t.Fatalf("the stray record should have been removed")
+	}
+	if values := api.record("example.com", "@", "NS"); !areStringSlicesEqual(values, fakeLiveDNSNameservers) {
+		t.Fatalf("the apex NS record should have been kept, got %v", values)
+	}
+	p.planEmpty("gandi_livedns_zonefile", state, config)
+
+	// A record changed by hand shows up as a diff of the content
+	api.setRecord("example.com", livedns.DomainRecord{RrsetName: "www", RrsetType: "A", RrsetTTL: 300, RrsetValues: []string{"10.0.0.1"}})
+	state = p.refresh("gandi_livedns_zonefile", state)
+	if !strings.Contains(state.Attributes["content"], "www 300 IN A 10.0.0.1\n") {
+		t.Fatalf("the content should contain the drift, got %q", state.Attributes["content"])
+	}
+	diff, diags := p.plan("gandi_livedns_zonefile", state, config)
+	if diags.HasError() || diff == nil || diff.Attributes["content"] == nil {
+		t.Fatalf("expected a diff of the content, got %v %v", diff, diags)
+	}
+	state = p.apply("gandi_livedns_zonefile", state, config)
+	if values := api.record("example.com", "www", "A"); !areStringSlicesEqual(values, []string{"192.168.0.1", "192.168.0.2"}) {
+		t.Fatalf("the www record should have been restored, got %v", values)
+	}
+
+	imported := p.importState("gandi_livedns_zonefile", "example.com")
+	testCheckAttributes(t, imported, map[string]string{
+		"zone":    "example.com",
+		"content": state.Attributes["content"],
+	})
+
+	data := p.readDataSource("gandi_livedns_zonefile", map[string]interface{}{"zone": "example.com"})
+	testCheckAttributes(t, data, map[string]string{"content": state.Attributes["content"]})
+
+	p.destroy("gandi_livedns_zonefile", state)
+	if records := api.zoneRecords("example.com"); len(records) != 1 || records[0].RrsetType != "NS" {
+		t.Fatalf("only the apex NS record should remain, got %v", records)
+	}
+}
+
+func TestOfflineZoneFile_invalid(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addZone("example.com")
+	p := newTestOfflineProvider(t, api)
+	_, diags := p.plan("gandi_livedns_zonefile", nil, map[string]interface{}{
+		"zone":    "example.com",
+		"content": "www.example.org. 300 IN A 192.168.0.1",
+	})
+	if !diags.HasError() {
+		t.Fatalf("expected the plan to fail")
+	}
+}
@@ -1,11 +1,14 @@
 package gandi
 
 import (
+	"context"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/go-gandi/go-gandi/email"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -40,19 +43,34 @@ func resourceMailbox() *schema.Resource {
 				Optional:    true,
 				Description: "Aliases for email",
 			},
+			"address": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The email address of the mailbox",
+			},
+			"href": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The href of the mailbox",
+			},
+			"quota_used": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The mailbox quota used",
+			},
 		},
-		Create: resourceMailboxCreate,
-		Delete: resourceMailboxDelete,
-		Read:   resourceMailboxRead,
-		Update: resourceMailboxUpdate,
+		CreateContext: resourceMailboxCreate,
+		DeleteContext: resourceMailboxDelete,
+		ReadContext:   resourceMailboxRead,
+		UpdateContext: resourceMailboxUpdate,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceMailboxImport,
 		},
 	}
 }
 
-func resourceMailboxCreate(d *schema.ResourceData, meta interface{}) (err error) {
-	client := meta.(*clients).Email
+func resourceMailboxCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
 	domain := d.Get("domain").(string)
 	login := d.Get("login").(string)
 
@@ -69,17 +87,25 @@ func resourceMailboxCreate(d *schema.ResourceData, meta interface{}) (err error)
 		Password:    d.Get("password").(string),
 	}
 
-	err = client.CreateEmail(domain, request)
+	err := c.callNonIdempotent(ctx, func() error { return c.Email.CreateEmail(domain, request) })
 	if err != nil {
-		return
+		return diagFromRequestError(err, resourceMailbox().Schema, nil)
 	}
 
 	// Sent, got 202 response. What's now?
-	time.Sleep(2 * time.Second)
+	select {
+	case <-ctx.Done():
+		return diag.FromErr(ctx.Err())
+	case <-time.After(2 * time.Second):
+	}
 
-	boxes, err := client.ListMailboxes(domain)
-	if err != nil {
+	var boxes []email.ListMailboxResponse
+	err = c.call(ctx, func() (err error) {
+		boxes, err = c.Email.ListMailboxes(domain)
 		return
+	})
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	for _, b := range boxes {
@@ -89,44 +115,48 @@ func resourceMailboxCreate(d *schema.ResourceData, meta interface{}) (err error)
 		}
 	}
 
-	return resourceMailboxRead(d, meta)
+	return resourceMailboxRead(ctx, d, meta)
 }
 
-func resourceMailboxRead(d *schema.ResourceData, meta interface{}) (err error) {
-	client := meta.(*clients).Email
+func resourceMailboxRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
 	domain := d.Get("domain").(string)
 
-	found, err := client.GetMailbox(domain, d.Id())
-	if err != nil {
+	var found email.MailboxResponse
+	err := c.call(ctx, func() (err error) {
+		found, err = c.Email.GetMailbox(domain, d.Id())
 		return
+	})
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	if err = d.Set("address", found.Address); err != nil {
-		return fmt.Errorf("failed to set address for %s: %s", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set address for %s: %s", d.Id(), err))
 	}
 	if err = d.Set("aliases", found.Aliases); err != nil {
-		return fmt.Errorf("failed to set aliases for %s: %s", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set aliases for %s: %s", d.Id(), err))
 	}
 	if err = d.Set("domain", found.Domain); err != nil {
-		return fmt.Errorf("failed to set domain for %s: %s", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set domain for %s: %s", d.Id(), err))
 	}
 	if err = d.Set("href", found.Href); err != nil {
-		return fmt.Errorf("failed to set href for %s: %s", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set href for %s: %s", d.Id(), err))
 	}
 	if err = d.Set("quota_used", found.QuotaUsed); err != nil {
-		return fmt.Errorf("failed to set quota_used for %s: %s", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set quota_used for %s: %s", d.Id(), err))
 	}
 	if err = d.Set("login", found.Login); err != nil {
-		return fmt.Errorf("failed to set login for %s: %s", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set login for %s: %s", d.Id(), err))
 	}
 	if err = d.Set("mailbox_type", found.MailboxType); err != nil {
-		return fmt.Errorf("failed to set mailbox_type for %s: %s", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set mailbox_type for %s: %s", d.Id(), err))
 	}
-	return
+	return nil
 }
 
-func resourceMailboxUpdate(d *schema.ResourceData, meta interface{}) (err error) {
-	client := meta.(*clients).Email
+func resourceMailboxUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
 	domain := d.Get("domain").(string)
 
 	var aliases []string
@@ -141,19 +171,34 @@ func resourceMailboxUpdate(d *schema.ResourceData, meta interface{}) (err error)
 		Password: d.Get("password").(string),
 	}
 
-	if err = client.UpdateEmail(domain, d.Id(), request); err != nil {
-		return
+	if err := c.callNonIdempotent(ctx, func() error { return c.Email.UpdateEmail(domain, d.Id(), request) }); err != nil {
+		return diagFromRequestError(err, resourceMailbox().Schema, nil)
 	}
-	return
+	return nil
 }
 
-func resourceMailboxDelete(d *schema.ResourceData, meta interface{}) (err error) {
-	client := meta.(*clients).Email
+func resourceMailboxDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
 	domain := d.Get("domain").(string)
 
-	if err = client.DeleteEmail(domain, d.Id()); err != nil {
-		return
+	if err := c.call(ctx, func() error { return c.Email.DeleteEmail(domain, d.Id()) }); err != nil {
+		return diag.FromErr(err)
 	}
 
-	return
+	return nil
+}
+
+// resourceMailboxImport imports a mailbox from a
+// '{domain}/{mailbox_id}' ID since the domain is required to get the
+// mailbox.
+func resourceMailboxImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), "/", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("id format should be '{domain}/{mailbox_id}'")
+	}
+	if err := d.Set("domain", parts[0]); err != nil {
+		return nil, fmt.Errorf("failed to set domain for %s: %s", d.Id(), err)
+	}
+	d.SetId(parts[1])
+	return []*schema.ResourceData{d}, nil
 }
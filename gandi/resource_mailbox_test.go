@@ -0,0 +1,57 @@
+package gandi
+
+import (
+	"testing"
+)
+
+func TestOfflineMailbox_basic(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	p := newTestOfflineProvider(t, api)
+	config := map[string]interface{}{
+		"domain":   "example.com",
+		"login":    "john",
+		"password": "secret",
+		"aliases":  []interface{}{"jdoe"},
+	}
+
+	state := p.apply("gandi_mailbox", nil, config)
+	if state.ID == "" {
+		t.Fatalf("the mailbox ID should have been set")
+	}
+	testCheckAttributes(t, state, map[string]string{
+		"address":      "john@example.com",
+		"mailbox_type": "standard",
+		"aliases.#":    "1",
+	})
+	p.planEmpty("gandi_mailbox", state, config)
+
+	config["aliases"] = []interface{}{"jdoe", "johnny"}
+	state = p.apply("gandi_mailbox", state, config)
+	if aliases := api.mailboxes["example.com"][state.ID].Aliases; len(aliases) != 2 {
+		t.Fatalf("the mailbox aliases should have been updated, got %v", aliases)
+	}
+
+	data := p.readDataSource("gandi_mailbox", map[string]interface{}{
+		"domain":     "example.com",
+		"mailbox_id": state.ID,
+	})
+	testCheckAttributes(t, data, map[string]string{
+		"address":   "john@example.com",
+		"login":     "john",
+		"aliases.#": "2",
+	})
+
+	imported := p.importState("gandi_mailbox", "example.com/"+state.ID)
+	testCheckAttributes(t, imported, map[string]string{
+		"id":           state.ID,
+		"domain":       "example.com",
+		"login":        "john",
+		"mailbox_type": "standard",
+		"aliases.#":    "2",
+	})
+
+	p.destroy("gandi_mailbox", state)
+	if len(api.mailboxes["example.com"]) != 0 {
+		t.Fatalf("the mailbox should have been deleted")
+	}
+}
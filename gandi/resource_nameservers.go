@@ -6,7 +6,6 @@ import (
 	"reflect"
 	"time"
 
-	"github.com/go-gandi/go-gandi/domain"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -15,9 +14,9 @@ import (
 func resourceNameservers() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceNameserversCreate,
-		Read:          resourceNameserversRead,
+		ReadContext:   resourceNameserversRead,
 		UpdateContext: resourceNameserversUpdate,
-		Delete:        resourceNameserversDelete,
+		DeleteContext: resourceNameserversDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -39,78 +38,89 @@ func resourceNameservers() *schema.Resource {
 }
 
 func resourceNameserversCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*clients).Domain
+	c := meta.(*clients)
 
 	domain := d.Get("domain").(string)
 	d.SetId(domain)
 	nameservers := expandArray(d.Get("nameservers").([]interface{}))
 
-	if err := client.UpdateNameServers(domain, nameservers); err != nil {
-		return diag.FromErr(err)
+	c.Cache.invalidateDomain(domain)
+	if err := c.call(ctx, func() error { return c.Domain.UpdateNameServers(domain, nameservers) }); err != nil {
+		return diagFromRequestError(err, resourceNameservers().Schema, nil)
 	}
 
 	err := resource.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-		return retryableGetNameServers(client, domain, nameservers)
+		return retryableGetNameServers(ctx, c, domain, nameservers)
 	})
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	return diag.FromErr(resourceNameserversRead(d, meta))
+	return resourceNameserversRead(ctx, d, meta)
 }
 
-func resourceNameserversRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*clients).Domain
+func resourceNameserversRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
 	domain := d.Id()
-	nameservers, err := client.GetNameServers(domain)
+	var nameservers []string
+	err := c.call(ctx, func() (err error) {
+		nameservers, err = c.Domain.GetNameServers(domain)
+		return
+	})
 	if err != nil {
 		d.SetId("")
-		return err
+		return diag.FromErr(err)
 	}
 	if err = d.Set("domain", domain); err != nil {
-		return fmt.Errorf("failed to set domain name for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set domain name for %s: %w", d.Id(), err))
 	}
 	if err = d.Set("nameservers", nameservers); err != nil {
-		return fmt.Errorf("failed to set nameservers for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set nameservers for %s: %w", d.Id(), err))
 	}
 	return nil
 }
 
 func resourceNameserversUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*clients).Domain
+	c := meta.(*clients)
 	domain := d.Get("domain").(string)
 	nameservers := expandArray(d.Get("nameservers").([]interface{}))
 
 	if d.HasChange("nameservers") {
-		if err := client.UpdateNameServers(domain, nameservers); err != nil {
-			return diag.FromErr(err)
+		c.Cache.invalidateDomain(domain)
+		if err := c.call(ctx, func() error { return c.Domain.UpdateNameServers(domain, nameservers) }); err != nil {
+			return diagFromRequestError(err, resourceNameservers().Schema, nil)
 		}
 	}
 	err := resource.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-		return retryableGetNameServers(client, domain, nameservers)
+		return retryableGetNameServers(ctx, c, domain, nameservers)
 	})
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	return diag.FromErr(resourceNameserversRead(d, meta))
+	return resourceNameserversRead(ctx, d, meta)
 }
 
 // resourceNameserversDelete deletes the nameservers resource and
 // re-enable the liveDNS nameserver on the domain, which is the
 // default domain configurtion.
-func resourceNameserversDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*clients).Domain
+func resourceNameserversDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
 	domain := d.Id()
 	// Removing nameservers consits of enabling livedns, which is
 	// the initial domain state.
-	if err := client.EnableLiveDNS(domain); err != nil {
-		return err
+	c.Cache.invalidateDomain(domain)
+	if err := c.callNonIdempotent(ctx, func() error { return c.Domain.EnableLiveDNS(domain) }); err != nil {
+		return diag.FromErr(err)
 	}
 	d.SetId("")
 	return nil
 }
 
-func retryableGetNameServers(client *domain.Domain, domain string, nameservers []string) *resource.RetryError {
-	nameserversFromApi, err := client.GetNameServers(domain)
+func retryableGetNameServers(ctx context.Context, c *clients, domain string, nameservers []string) *resource.RetryError {
+	var nameserversFromApi []string
+	err := c.call(ctx, func() (err error) {
+		nameserversFromApi, err = c.Domain.GetNameServers(domain)
+		return
+	})
 	if err != nil {
 		return resource.NonRetryableError(
 			fmt.Errorf("Error getting nameservers of domain %s: %s", domain, err))
@@ -27,3 +27,39 @@ func testAccConfigNameservers() string {
           }
 	`
 }
+
+func TestOfflineNameservers_basic(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	api.addDomain("example.com")
+	p := newTestOfflineProvider(t, api)
+	config := map[string]interface{}{
+		"domain":      "example.com",
+		"nameservers": []interface{}{"ns1.example.foo", "ns2.example.foo"},
+	}
+
+	state := p.apply("gandi_nameservers", nil, config)
+	testCheckAttributes(t, state, map[string]string{
+		"id":            "example.com",
+		"nameservers.#": "2",
+		"nameservers.0": "ns1.example.foo",
+	})
+	p.planEmpty("gandi_nameservers", state, config)
+
+	config["nameservers"] = []interface{}{"ns3.example.foo"}
+	state = p.apply("gandi_nameservers", state, config)
+	testCheckAttributes(t, state, map[string]string{
+		"nameservers.#": "1",
+		"nameservers.0": "ns3.example.foo",
+	})
+
+	imported := p.importState("gandi_nameservers", "example.com")
+	testCheckAttributes(t, imported, map[string]string{
+		"domain":        "example.com",
+		"nameservers.0": "ns3.example.foo",
+	})
+
+	p.destroy("gandi_nameservers", state)
+	if !api.domains["example.com"].liveDNS {
+		t.Fatalf("LiveDNS should have been enabled back on the domain")
+	}
+}
@@ -14,7 +14,7 @@ import (
 func resourceSimpleHostingInstance() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceSimpleHostingInstanceCreate,
-		Read:          resourceSimpleHostingInstanceRead,
+		ReadContext:   resourceSimpleHostingInstanceRead,
 		DeleteContext: resourceSimpleHostingInstanceDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
@@ -55,58 +55,75 @@ func resourceSimpleHostingInstance() *schema.Resource {
 	}
 }
 
-func resourceSimpleHostingInstanceRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*clients).SimpleHosting
+func resourceSimpleHostingInstanceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
 	id := d.Id()
-	found, err := client.GetInstance(id)
+	var found simplehosting.Instance
+	err := c.call(ctx, func() (err error) {
+		found, err = c.SimpleHosting.GetInstance(id)
+		return
+	})
 
 	if err != nil {
-		return fmt.Errorf("unknown simplehosting instance '%s': %w", id, err)
+		return diag.FromErr(fmt.Errorf("unknown simplehosting instance '%s': %w", id, err))
 	}
 	d.SetId(found.ID)
 	if err = d.Set("name", found.Name); err != nil {
-		return fmt.Errorf("failed to set name for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set name for %s: %w", d.Id(), err))
 	}
 	if err = d.Set("size", found.Size); err != nil {
-		return fmt.Errorf("failed to set size for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set size for %s: %w", d.Id(), err))
 	}
 	if err = d.Set("location", found.Datacenter.Region); err != nil {
-		return fmt.Errorf("failed to set location for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set location for %s: %w", d.Id(), err))
 	}
 	if err = d.Set("database_name", found.Database.Name); err != nil {
-		return fmt.Errorf("failed to set database_name for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set database_name for %s: %w", d.Id(), err))
 	}
 	if err = d.Set("language_name", found.Language.Name); err != nil {
-		return fmt.Errorf("failed to set language_name for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set language_name for %s: %w", d.Id(), err))
 	}
 	return nil
 }
 
+// simpleHostingInstanceAPIFields maps the fields of the Gandi API
+// errors to the attributes of the instance, when their names differ
+var simpleHostingInstanceAPIFields = map[string]string{
+	"type.database": "database_name",
+	"type.language": "language_name",
+}
+
 func resourceSimpleHostingInstanceCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*clients).SimpleHosting
-	instanceId, err := client.CreateInstance(
-		simplehosting.CreateInstanceRequest{
-			Name:     d.Get("name").(string),
-			Location: d.Get("location").(string),
-			Size:     d.Get("size").(string),
-			Type: &simplehosting.InstanceType{
-				Database: &simplehosting.Database{
-					Name: d.Get("database_name").(string),
-				},
-				Language: &simplehosting.Language{
-					Name: d.Get("language_name").(string),
-				},
+	c := meta.(*clients)
+	request := simplehosting.CreateInstanceRequest{
+		Name:     d.Get("name").(string),
+		Location: d.Get("location").(string),
+		Size:     d.Get("size").(string),
+		Type: &simplehosting.InstanceType{
+			Database: &simplehosting.Database{
+				Name: d.Get("database_name").(string),
+			},
+			Language: &simplehosting.Language{
+				Name: d.Get("language_name").(string),
 			},
 		},
-	)
+	}
+	var instanceId string
+	err := c.callNonIdempotent(ctx, func() (err error) {
+		instanceId, err = c.SimpleHosting.CreateInstance(request)
+		return
+	})
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromRequestError(err, resourceSimpleHostingInstance().Schema, simpleHostingInstanceAPIFields)
 	}
 	d.SetId(instanceId)
 
 	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-		client := meta.(*clients).SimpleHosting
-		instance, err := client.GetInstance(instanceId)
+		var instance simplehosting.Instance
+		err := c.call(ctx, func() (err error) {
+			instance, err = c.SimpleHosting.GetInstance(instanceId)
+			return
+		})
 		if err != nil {
 			return resource.NonRetryableError(fmt.Errorf("Error getting instance %s: %s", instanceId, err))
 		}
@@ -118,20 +135,29 @@ func resourceSimpleHostingInstanceCreate(ctx context.Context, d *schema.Resource
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	return diag.FromErr(resourceSimpleHostingInstanceRead(d, meta))
+	return resourceSimpleHostingInstanceRead(ctx, d, meta)
 }
 
 func resourceSimpleHostingInstanceDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*clients).SimpleHosting
+	c := meta.(*clients)
 	instanceId := d.Id()
-	_, err := client.DeleteInstance(instanceId)
+	err := c.call(ctx, func() error {
+		_, err := c.SimpleHosting.DeleteInstance(instanceId)
+		return err
+	})
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	return diag.FromErr(resource.RetryContext(ctx, d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
-		_, err := client.GetInstance(instanceId)
+		err := c.call(ctx, func() error {
+			_, err := c.SimpleHosting.GetInstance(instanceId)
+			return err
+		})
 		if err != nil {
+			if ctx.Err() != nil {
+				return resource.NonRetryableError(err)
+			}
 			return nil
 		}
 		return resource.RetryableError(fmt.Errorf("The instance %s have not been deleted yet", instanceId))
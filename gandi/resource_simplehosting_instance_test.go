@@ -37,3 +37,35 @@ func testAccConfigInstance() string {
           }
 	`
 }
+
+func TestOfflineSimpleHostingInstance_basic(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	p := newTestOfflineProvider(t, api)
+	config := map[string]interface{}{
+		"name":          "create",
+		"size":          "s+",
+		"database_name": "mysql",
+		"language_name": "php",
+		"location":      "FR",
+	}
+
+	state := p.apply("gandi_simplehosting_instance", nil, config)
+	if _, ok := api.instances[state.ID]; !ok {
+		t.Fatalf("the instance %s should have been created", state.ID)
+	}
+	p.planEmpty("gandi_simplehosting_instance", state, config)
+
+	imported := p.importState("gandi_simplehosting_instance", state.ID)
+	testCheckAttributes(t, imported, map[string]string{
+		"name":          "create",
+		"size":          "s+",
+		"database_name": "mysql",
+		"language_name": "php",
+		"location":      "FR",
+	})
+
+	p.destroy("gandi_simplehosting_instance", state)
+	if len(api.instances) != 0 {
+		t.Fatalf("the instance should have been deleted")
+	}
+}
@@ -3,6 +3,7 @@ package gandi
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/go-gandi/go-gandi/certificate"
@@ -15,10 +16,10 @@ import (
 func resourceSimpleHostingVhost() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceSimpleHostingVhostCreate,
-		Read:          resourceSimpleHostingVhostRead,
+		ReadContext:   resourceSimpleHostingVhostRead,
 		DeleteContext: resourceSimpleHostingVhostDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceSimpleHostingVhostImport,
 		},
 		Schema: map[string]*schema.Schema{
 			"instance_id": {
@@ -58,14 +59,17 @@ func resourceSimpleHostingVhost() *schema.Resource {
 }
 
 // freeCertificateCreate creates a free certificate for the Vhost.
-func freeCertificateCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*clients).Certificate
-	response, err := client.CreateCertificate(
-		certificate.CreateCertificateRequest{
-			CN:      d.Get("fqdn").(string),
-			Package: "cert_free_1_0_0",
-		},
-	)
+func freeCertificateCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*clients)
+	request := certificate.CreateCertificateRequest{
+		CN:      d.Get("fqdn").(string),
+		Package: "cert_free_1_0_0",
+	}
+	var response certificate.CreateCertificateResponse
+	err := c.callNonIdempotent(ctx, func() (err error) {
+		response, err = c.Certificate.CreateCertificate(request)
+		return
+	})
 	if err != nil {
 		return err
 	}
@@ -77,18 +81,26 @@ func freeCertificateCreate(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func freeCertificateDelete(d *schema.ResourceData, meta interface{}) (err error) {
-	client := meta.(*clients).Certificate
+func freeCertificateDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*clients)
 	certificateId := d.Get("certificate_id").(string)
 	if certificateId == "" {
 		return nil
 	}
-	_, err = client.DeleteCertificate(certificateId)
-	return
+	return c.call(ctx, func() error {
+		_, err := c.Certificate.DeleteCertificate(certificateId)
+		return err
+	})
+}
+
+// simpleHostingVhostAPIFields maps the fields of the Gandi API errors
+// to the attributes of the vhost, when their names differ
+var simpleHostingVhostAPIFields = map[string]string{
+	"linked_dns_zone": "linked_dns_zone_alteration",
 }
 
 func resourceSimpleHostingVhostCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*clients).SimpleHosting
+	c := meta.(*clients)
 	instanceId := d.Get("instance_id").(string)
 	fqdn := d.Get("fqdn").(string)
 	request := simplehosting.CreateVhostRequest{
@@ -100,18 +112,21 @@ func resourceSimpleHostingVhostCreate(ctx context.Context, d *schema.ResourceDat
 			AlowAlterationOverride: true,
 		}
 	}
-	_, err := client.CreateVhost(
-		instanceId,
-		request,
-	)
+	err := c.callNonIdempotent(ctx, func() error {
+		_, err := c.SimpleHosting.CreateVhost(instanceId, request)
+		return err
+	})
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromRequestError(err, resourceSimpleHostingVhost().Schema, simpleHostingVhostAPIFields)
 	}
 	d.SetId(fqdn)
 
 	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-		instance, err := client.GetVhost(instanceId, fqdn)
-
+		var instance simplehosting.Vhost
+		err := c.call(ctx, func() (err error) {
+			instance, err = c.SimpleHosting.GetVhost(instanceId, fqdn)
+			return
+		})
 		if err != nil {
 			return resource.NonRetryableError(fmt.Errorf("Error getting vhost %s of instance %s: %w", instanceId, fqdn, err))
 		}
@@ -128,7 +143,7 @@ func resourceSimpleHostingVhostCreate(ctx context.Context, d *schema.ResourceDat
 	// Note it is plan to let the SimpleHosting API manage the
 	// certificate. For now, we have to create in the Terraform
 	// provider.
-	err = freeCertificateCreate(d, meta)
+	err = freeCertificateCreate(ctx, d, meta)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -138,49 +153,59 @@ func resourceSimpleHostingVhostCreate(ctx context.Context, d *schema.ResourceDat
 	// created:/
 	applicationName := d.Get("application").(string)
 	if applicationName != "" {
-		_, err = client.UpdateVhost(
-			instanceId,
-			fqdn,
-			simplehosting.PatchVhostRequest{
-				Application: &simplehosting.Application{
-					Name: applicationName,
+		err = c.callNonIdempotent(ctx, func() error {
+			_, err := c.SimpleHosting.UpdateVhost(
+				instanceId,
+				fqdn,
+				simplehosting.PatchVhostRequest{
+					Application: &simplehosting.Application{
+						Name: applicationName,
+					},
 				},
-			},
-		)
+			)
+			return err
+		})
 		if err != nil {
-			return diag.FromErr(err)
+			return diagFromRequestError(err, resourceSimpleHostingVhost().Schema, simpleHostingVhostAPIFields)
 		}
 	}
-	return diag.FromErr(resourceSimpleHostingVhostRead(d, meta))
+	return resourceSimpleHostingVhostRead(ctx, d, meta)
 }
 
-func resourceSimpleHostingVhostRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*clients).SimpleHosting
+func resourceSimpleHostingVhostRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*clients)
 	instanceId := d.Get("instance_id").(string)
 	fqdn := d.Get("fqdn").(string)
-	found, err := client.GetVhost(instanceId, fqdn)
+	var found simplehosting.Vhost
+	err := c.call(ctx, func() (err error) {
+		found, err = c.SimpleHosting.GetVhost(instanceId, fqdn)
+		return
+	})
 
 	if err != nil {
-		return fmt.Errorf("unknown simplehosting vhost '%s' of instance '%s': %w", instanceId, fqdn, err)
+		return diag.FromErr(fmt.Errorf("unknown simplehosting vhost '%s' of instance '%s': %w", instanceId, fqdn, err))
 	}
 
 	d.SetId(found.FQDN)
 	if err = d.Set("linked_dns_zone_alteration", found.LinkedDNSZone.AllowAlteration); err != nil {
-		return fmt.Errorf("failed to set linked_dns_zone_alteration for %s: %w", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("failed to set linked_dns_zone_alteration for %s: %w", d.Id(), err))
 	}
 	if found.Application != nil {
 		if err = d.Set("application", found.Application.Name); err != nil {
-			return fmt.Errorf("failed to set application for %s: %w", d.Id(), err)
+			return diag.FromErr(fmt.Errorf("failed to set application for %s: %w", d.Id(), err))
 		}
 	}
 	return nil
 }
 
 func resourceSimpleHostingVhostDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*clients).SimpleHosting
+	c := meta.(*clients)
 	instanceId := d.Get("instance_id").(string)
 	fqdn := d.Get("fqdn").(string)
-	_, err := client.DeleteVhost(instanceId, fqdn)
+	err := c.call(ctx, func() error {
+		_, err := c.SimpleHosting.DeleteVhost(instanceId, fqdn)
+		return err
+	})
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -188,11 +213,17 @@ func resourceSimpleHostingVhostDelete(ctx context.Context, d *schema.ResourceDat
 	// Note it is plan to let the SimpleHosting API manage the
 	// certificate. For now, we try to delete it but don't care
 	// about potential error.
-	_ = freeCertificateDelete(d, meta)
+	_ = freeCertificateDelete(ctx, d, meta)
 
 	return diag.FromErr(resource.RetryContext(ctx, d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
-		_, err := client.GetVhost(instanceId, fqdn)
+		err := c.call(ctx, func() error {
+			_, err := c.SimpleHosting.GetVhost(instanceId, fqdn)
+			return err
+		})
 		if err != nil {
+			if ctx.Err() != nil {
+				return resource.NonRetryableError(err)
+			}
 			return nil
 		}
 		// We should check the return code is 404 but this is
@@ -201,3 +232,21 @@ func resourceSimpleHostingVhostDelete(ctx context.Context, d *schema.ResourceDat
 		return resource.RetryableError(fmt.Errorf("The vhost %s of instance %s have not been deleted yet", fqdn, instanceId))
 	}))
 }
+
+// resourceSimpleHostingVhostImport imports a vhost from a
+// '{instance_id}/{fqdn}' ID since the instance is required to get the
+// vhost.
+func resourceSimpleHostingVhostImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), "/", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("id format should be '{instance_id}/{fqdn}'")
+	}
+	if err := d.Set("instance_id", parts[0]); err != nil {
+		return nil, fmt.Errorf("failed to set instance_id for %s: %w", d.Id(), err)
+	}
+	if err := d.Set("fqdn", parts[1]); err != nil {
+		return nil, fmt.Errorf("failed to set fqdn for %s: %w", d.Id(), err)
+	}
+	d.SetId(parts[1])
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,49 @@
+package gandi
+
+import (
+	"testing"
+)
+
+func TestOfflineSimpleHostingVhost_basic(t *testing.T) {
+	api := newFakeGandiAPI(t)
+	p := newTestOfflineProvider(t, api)
+	instance := p.apply("gandi_simplehosting_instance", nil, map[string]interface{}{
+		"name":          "create",
+		"size":          "s+",
+		"database_name": "mysql",
+		"language_name": "php",
+		"location":      "FR",
+	})
+	config := map[string]interface{}{
+		"instance_id": instance.ID,
+		"fqdn":        "www.example.com",
+		"application": "wordpress",
+	}
+
+	state := p.apply("gandi_simplehosting_vhost", nil, config)
+	testCheckAttributes(t, state, map[string]string{
+		"id":                         "www.example.com",
+		"linked_dns_zone_alteration": "true",
+		"application":                "wordpress",
+	})
+	if state.Attributes["certificate_id"] == "" {
+		t.Fatalf("a free certificate should have been created")
+	}
+	p.planEmpty("gandi_simplehosting_vhost", state, config)
+
+	imported := p.importState("gandi_simplehosting_vhost", instance.ID+"/www.example.com")
+	testCheckAttributes(t, imported, map[string]string{
+		"id":          "www.example.com",
+		"instance_id": instance.ID,
+		"fqdn":        "www.example.com",
+		"application": "wordpress",
+	})
+
+	p.destroy("gandi_simplehosting_vhost", state)
+	if len(api.vhosts[instance.ID]) != 0 {
+		t.Fatalf("the vhost should have been deleted")
+	}
+	if len(api.certificates) != 0 {
+		t.Fatalf("the free certificate should have been deleted")
+	}
+}
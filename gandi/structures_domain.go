@@ -3,6 +3,7 @@ package gandi
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/go-gandi/go-gandi/domain"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -117,3 +118,136 @@ func validateCountryCode(val interface{}, key string) (warns []string, errs []er
 	}
 	return
 }
+
+func validateDomainDuration(val interface{}, key string) (warns []string, errs []error) {
+	v := val.(int)
+	if v < 1 || v > 10 {
+		errs = append(errs, fmt.Errorf("%q must be between 1 and 10 years. Got %d", key, v))
+	}
+	return
+}
+
+func validateCurrency(val interface{}, key string) (warns []string, errs []error) {
+	v := val.(string)
+	valid := len(v) == 3
+	for _, c := range v {
+		if c < 'A' || c > 'Z' {
+			valid = false
+		}
+	}
+	if !valid {
+		errs = append(errs, fmt.Errorf("%q must be a three letter currency code, such as EUR. Got %s", key, v))
+	}
+	return
+}
+
+// domainMetadataDates are the dates of a domain exposed as attributes
+var domainMetadataDates = map[string]struct {
+	description string
+	date        func(*domain.ResponseDates) *time.Time
+}{
+	"created_at": {
+		"The date the domain was created at Gandi",
+		func(d *domain.ResponseDates) *time.Time { return d.CreatedAt },
+	},
+	"updated_at": {
+		"The date of the last update of the domain",
+		func(d *domain.ResponseDates) *time.Time { return d.UpdatedAt },
+	},
+	"registry_created_at": {
+		"The date the domain was registered at the registry",
+		func(d *domain.ResponseDates) *time.Time { return d.RegistryCreatedAt },
+	},
+	"registry_ends_at": {
+		"The expiration date of the domain at the registry",
+		func(d *domain.ResponseDates) *time.Time { return d.RegistryEndsAt },
+	},
+	"renew_begins_at": {
+		"The date from which the domain can be renewed",
+		func(d *domain.ResponseDates) *time.Time { return d.RenewBeginsAt },
+	},
+	"hold_begins_at": {
+		"The date the domain is put on hold if it isn't renewed",
+		func(d *domain.ResponseDates) *time.Time { return d.HoldBeginsAt },
+	},
+	"hold_ends_at": {
+		"The end of the hold period of the domain",
+		func(d *domain.ResponseDates) *time.Time { return d.HoldEndsAt },
+	},
+	"deletes_at": {
+		"The date the domain is deleted if it isn't renewed",
+		func(d *domain.ResponseDates) *time.Time { return d.DeletesAt },
+	},
+	"authinfo_expires_at": {
+		"The expiration date of the authorization code of the domain",
+		func(d *domain.ResponseDates) *time.Time { return d.AuthInfoExpiresAt },
+	},
+}
+
+// domainMetadataSchema returns the computed attributes describing the
+// lifecycle of a domain, shared by the resource and the data source.
+func domainMetadataSchema() map[string]*schema.Schema {
+	s := map[string]*schema.Schema{
+		"tld": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The TLD of the domain",
+		},
+		"sharing_id": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The ID of the organization owning the domain",
+		},
+		"status": {
+			Type:        schema.TypeList,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Computed:    true,
+			Description: "The statuses of the domain, such as 'clientTransferProhibited'",
+		},
+		"authinfo_set": {
+			Type:        schema.TypeBool,
+			Computed:    true,
+			Description: "Whether the domain has an authorization code",
+		},
+	}
+	for key, date := range domainMetadataDates {
+		s[key] = &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: date.description + ", in the RFC 3339 format",
+		}
+	}
+	return s
+}
+
+// setDomainMetadata sets the attributes of domainMetadataSchema
+func setDomainMetadata(d *schema.ResourceData, details domain.Details) error {
+	sharingID := details.SharingID
+	if sharingID == "" && details.SharingSpace != nil {
+		sharingID = details.SharingSpace.ID
+	}
+	status := details.Status
+	if status == nil {
+		status = []string{}
+	}
+	values := map[string]interface{}{
+		"tld":          details.TLD,
+		"sharing_id":   sharingID,
+		"status":       status,
+		"authinfo_set": details.AuthInfo != "",
+	}
+	for key, date := range domainMetadataDates {
+		values[key] = ""
+		if details.Dates != nil {
+			if t := date.date(details.Dates); t != nil {
+				values[key] = t.UTC().Format(time.RFC3339)
+			}
+		}
+	}
+	for key, value := range values {
+		if err := d.Set(key, value); err != nil {
+			return fmt.Errorf("failed to set %s for %s: %w", key, d.Id(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,325 @@
+package gandi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-gandi/go-gandi/config"
+	"github.com/go-gandi/go-gandi/types"
+)
+
+var (
+	// retryBaseDelay is the delay before the first retry of a request,
+	// doubled for each of the following ones
+	retryBaseDelay = 1 * time.Second
+	// retryMaxDelay caps the exponential backoff, but not the delays
+	// requested by the Gandi API with a Retry-After header
+	retryMaxDelay = 30 * time.Second
+	// requestAttemptTimeout bounds each attempt of a request
+	requestAttemptTimeout = config.Timeout
+)
+
+// requestTimeout is the timeout of the apiClient, which covers all the
+// attempts of a request and the waits between them. Each attempt is
+// bounded by requestAttemptTimeout.
+const requestTimeout = 10 * time.Minute
+
+// transportSettings are the settings of the providerTransport, set
+// from the provider configuration.
+type transportSettings struct {
+	// stop is canceled when Terraform stops the provider
+	stop context.Context
+	// limiter spaces the requests, nil when they are not limited
+	limiter *rateLimiter
+	// maxRetries is the number of retries of a throttled or failed
+	// request
+	maxRetries int
+}
+
+// providerTransport applies the settings of the provider to its
+// requests. It is the transport of the apiClient, and call applies the
+// same settings to the requests of go-gandi, which neither accepts a
+// context nor a transport. It aborts the in-flight requests of the
+// apiClient when Terraform stops the provider, on Ctrl-C for instance,
+// while call only stops waiting for those of go-gandi. It enforces the
+// requests per second budget of the provider, and retries with an
+// exponential backoff the requests throttled (429) or rejected by an
+// unavailable gateway (502, 503, 504).
+type providerTransport struct {
+	base     http.RoundTripper
+	settings transportSettings
+}
+
+// newProviderTransport returns the transport of a provider. Each
+// provider has its own transport: http.DefaultTransport is only used
+// to send the requests, and is never replaced.
+func newProviderTransport(settings transportSettings) *providerTransport {
+	return &providerTransport{base: http.DefaultTransport, settings: settings}
+}
+
+func (t *providerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	settings := t.settings
+	if settings.stop != nil {
+		if err := settings.stop.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+		if err := settings.limiter.wait(req.Context(), settings.stop); err != nil {
+			return nil, err
+		}
+		resp, err := t.roundTrip(req, settings.stop)
+		if err != nil || attempt >= settings.maxRetries || !retryable(req, resp) {
+			return resp, err
+		}
+
+		delay := retryDelay(resp, attempt)
+		// The connection can only be reused once the body is read
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+		log.Printf("[DEBUG] %s %s returned %s, retrying in %s (%d/%d)", req.Method, req.URL, resp.Status, delay, attempt+1, settings.maxRetries)
+		if err := sleep(req.Context(), settings.stop, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// roundTrip sends a single attempt of the request, aborted when the
+// provider is stopped or when requestAttemptTimeout is exceeded.
+func (t *providerTransport) roundTrip(req *http.Request, stop context.Context) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), requestAttemptTimeout)
+	if stop != nil {
+		go func() {
+			select {
+			case <-stop.Done():
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+	}
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// The request context must live until the body is read
+	resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// retryable returns whether the request can be sent again after the
+// response. Throttled requests have not been processed and are always
+// retried, while the other failures are only retried for the
+// idempotent methods. The requests whose body can't be read again are
+// never retried.
+func retryable(req *http.Request, resp *http.Response) bool {
+	if req.Body != nil && req.GetBody == nil {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return retryableStatus(resp.StatusCode, true)
+	}
+	return retryableStatus(resp.StatusCode, false)
+}
+
+// retryDelay returns the delay before retrying a request, as requested
+// by the Retry-After header of the response if any, or following an
+// exponential backoff otherwise.
+func retryDelay(resp *http.Response, attempt int) time.Duration {
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		if date, err := http.ParseTime(retryAfter); err == nil {
+			if delay := time.Until(date); delay > 0 {
+				return delay
+			}
+			return 0
+		}
+	}
+	return backoffDelay(attempt)
+}
+
+// backoffDelay returns the exponential backoff delay before the retry
+// following the attempt.
+func backoffDelay(attempt int) time.Duration {
+	delay := retryBaseDelay
+	for i := 0; i < attempt && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return delay
+}
+
+// call runs a request of go-gandi with the settings of the transport.
+// The request can't be canceled: it runs in the background and call
+// returns as soon as ctx is done or the provider is stopped, while the
+// request is bounded by the timeout of the go-gandi clients,
+// requestAttemptTimeout. go-gandi doesn't return the headers of the
+// failed responses, so the retries follow the exponential backoff. The
+// requests which are not idempotent are only retried when throttled.
+func (t *providerTransport) call(ctx context.Context, idempotent bool, f func() error) error {
+	settings := t.settings
+	for attempt := 0; ; attempt++ {
+		if err := settings.limiter.wait(ctx, settings.stop); err != nil {
+			return err
+		}
+		err := callOnce(ctx, settings.stop, f)
+		status := requestErrorStatus(err)
+		if err == nil || attempt >= settings.maxRetries || !retryableStatus(status, idempotent) {
+			return err
+		}
+
+		delay := backoffDelay(attempt)
+		log.Printf("[DEBUG] request returned %d, retrying in %s (%d/%d)", status, delay, attempt+1, settings.maxRetries)
+		if err := sleep(ctx, settings.stop, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// callOnce runs f, returning early when ctx is done or the provider is
+// stopped. f keeps running in the background: the error then reports
+// that the request may still be processed.
+func callOnce(ctx context.Context, stop context.Context, f func() error) error {
+	var stopped <-chan struct{}
+	if stop != nil {
+		if err := stop.Err(); err != nil {
+			return err
+		}
+		stopped = stop.Done()
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- f()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("stopped waiting for the request, which may still be processed: %w", ctx.Err())
+	case <-stopped:
+		return fmt.Errorf("stopped waiting for the request, which may still be processed: %w", stop.Err())
+	}
+}
+
+// requestErrorStatus returns the status code of a failed go-gandi
+// request, or 0 if the error isn't an error response. The responses
+// which are not JSON are only reported in the message of the error.
+func requestErrorStatus(err error) int {
+	if err == nil {
+		return 0
+	}
+	var requestError *types.RequestError
+	if errors.As(err, &requestError) {
+		return requestError.StatusCode
+	}
+	var status int
+	if _, scanErr := fmt.Sscanf(err.Error(), "Response body is not json for status %d", &status); scanErr == nil {
+		return status
+	}
+	return 0
+}
+
+// retryableStatus returns whether a request which failed with the
+// status can be sent again, as retryable.
+func retryableStatus(status int, idempotent bool) bool {
+	switch status {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return idempotent
+	}
+	return false
+}
+
+// sleep waits for the delay, unless the context is done or the
+// provider is stopped before.
+func sleep(ctx context.Context, stop context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return nil
+	}
+	var stopped <-chan struct{}
+	if stop != nil {
+		stopped = stop.Done()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-stopped:
+		return stop.Err()
+	}
+}
+
+// rateLimiter spaces the requests sent by the provider to stay within
+// a requests per second budget.
+type rateLimiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// newRateLimiter returns a limiter allowing rate requests per second,
+// or nil if rate isn't positive.
+func newRateLimiter(rate float64) *rateLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / rate)}
+}
+
+// wait blocks until the next request is allowed. A nil limiter never
+// blocks.
+func (l *rateLimiter) wait(ctx context.Context, stop context.Context) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next = slot.Add(l.interval)
+	l.mu.Unlock()
+	return sleep(ctx, stop, slot.Sub(now))
+}
+
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,309 @@
+package gandi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-gandi/go-gandi/types"
+)
+
+// newTestTransport returns a providerTransport with its own base
+// transport, whose connections are not shared with the other tests.
+func newTestTransport(settings transportSettings) *providerTransport {
+	return &providerTransport{base: &http.Transport{}, settings: settings}
+}
+
+func TestStopTransport(t *testing.T) {
+	released := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-released:
+		}
+	}))
+	defer server.Close()
+	defer close(released)
+
+	stop, cancel := context.WithCancel(context.Background())
+	transport := newTestTransport(transportSettings{stop: stop})
+	client := &http.Client{Transport: transport}
+
+	done := make(chan error)
+	go func() {
+		resp, err := client.Get(server.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		done <- err
+	}()
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected the request to be canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the in-flight request should have been aborted when the provider is stopped")
+	}
+
+	if _, err := client.Get(server.URL); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected new requests to fail once the provider is stopped, got %v", err)
+	}
+}
+
+// newFlakyServer returns a server answering with the statuses, then
+// with 200 once they are all consumed, and the bodies it received.
+func newFlakyServer(t *testing.T, header http.Header, statuses ...int) (*httptest.Server, *[]string) {
+	var mu sync.Mutex
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		bodies = append(bodies, string(body))
+		if len(bodies) <= len(statuses) {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(statuses[len(bodies)-1])
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &bodies
+}
+
+func withRetryBaseDelay(t *testing.T, delay time.Duration) {
+	previous := retryBaseDelay
+	retryBaseDelay = delay
+	t.Cleanup(func() { retryBaseDelay = previous })
+}
+
+func TestProviderTransport_retry(t *testing.T) {
+	withRetryBaseDelay(t, time.Millisecond)
+
+	t.Run("idempotent request", func(t *testing.T) {
+		server, bodies := newFlakyServer(t, nil, http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusGatewayTimeout)
+		transport := newTestTransport(transportSettings{maxRetries: 3})
+		client := &http.Client{Transport: transport}
+
+		req, _ := http.NewRequest(http.MethodPut, server.URL, bytes.NewReader([]byte(`{"rrset_ttl":300}`)))
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected the request to succeed after the retries, got %s", resp.Status)
+		}
+		if len(*bodies) != 4 {
+			t.Fatalf("expected 4 attempts, got %d", len(*bodies))
+		}
+		for _, body := range *bodies {
+			if body != `{"rrset_ttl":300}` {
+				t.Fatalf("expected the body to be sent again on retries, got %q", body)
+			}
+		}
+	})
+
+	t.Run("max retries", func(t *testing.T) {
+		server, bodies := newFlakyServer(t, nil, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+		transport := newTestTransport(transportSettings{maxRetries: 1})
+		client := &http.Client{Transport: transport}
+
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusServiceUnavailable || len(*bodies) != 2 {
+			t.Fatalf("expected the last failure after 2 attempts, got %s after %d", resp.Status, len(*bodies))
+		}
+	})
+
+	t.Run("non idempotent request", func(t *testing.T) {
+		server, bodies := newFlakyServer(t, nil, http.StatusServiceUnavailable)
+		transport := newTestTransport(transportSettings{maxRetries: 3})
+		client := &http.Client{Transport: transport}
+
+		resp, err := client.Post(server.URL, "application/json", bytes.NewReader([]byte(`{}`)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusServiceUnavailable || len(*bodies) != 1 {
+			t.Fatalf("expected a POST not to be retried on 503, got %s after %d attempts", resp.Status, len(*bodies))
+		}
+	})
+
+	t.Run("throttled request", func(t *testing.T) {
+		server, bodies := newFlakyServer(t, http.Header{"Retry-After": {"0"}}, http.StatusTooManyRequests)
+		transport := newTestTransport(transportSettings{maxRetries: 3})
+		client := &http.Client{Transport: transport}
+
+		resp, err := client.Post(server.URL, "application/json", bytes.NewReader([]byte(`{}`)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || len(*bodies) != 2 {
+			t.Fatalf("expected a throttled POST to be retried, got %s after %d attempts", resp.Status, len(*bodies))
+		}
+	})
+
+	t.Run("canceled wait", func(t *testing.T) {
+		server, _ := newFlakyServer(t, http.Header{"Retry-After": {"3600"}}, http.StatusTooManyRequests)
+		transport := newTestTransport(transportSettings{maxRetries: 3})
+		client := &http.Client{Transport: transport}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		if _, err := client.Do(req); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected the wait for the Retry-After delay to be canceled, got %v", err)
+		}
+	})
+}
+
+func TestProviderTransport_call(t *testing.T) {
+	withRetryBaseDelay(t, time.Millisecond)
+
+	// failing returns a request failing with the statuses, then
+	// succeeding, and its number of attempts
+	failing := func(statuses ...int) (func() error, *int) {
+		attempts := 0
+		return func() error {
+			attempts++
+			if attempts <= len(statuses) {
+				return &types.RequestError{Err: errors.New("failed"), StatusCode: statuses[attempts-1]}
+			}
+			return nil
+		}, &attempts
+	}
+
+	t.Run("idempotent request", func(t *testing.T) {
+		transport := newTestTransport(transportSettings{maxRetries: 3})
+		f, attempts := failing(http.StatusServiceUnavailable, http.StatusTooManyRequests)
+		if err := transport.call(context.Background(), true, f); err != nil || *attempts != 3 {
+			t.Fatalf("expected the request to succeed after 3 attempts, got %v after %d", err, *attempts)
+		}
+	})
+
+	t.Run("non idempotent request", func(t *testing.T) {
+		transport := newTestTransport(transportSettings{maxRetries: 3})
+		f, attempts := failing(http.StatusServiceUnavailable)
+		if err := transport.call(context.Background(), false, f); requestErrorStatus(err) != http.StatusServiceUnavailable || *attempts != 1 {
+			t.Fatalf("expected a POST not to be retried on 503, got %v after %d attempts", err, *attempts)
+		}
+
+		f, attempts = failing(http.StatusTooManyRequests)
+		if err := transport.call(context.Background(), false, f); err != nil || *attempts != 2 {
+			t.Fatalf("expected a throttled POST to be retried, got %v after %d attempts", err, *attempts)
+		}
+	})
+
+	t.Run("max retries", func(t *testing.T) {
+		transport := newTestTransport(transportSettings{maxRetries: 1})
+		f, attempts := failing(http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+		if err := transport.call(context.Background(), true, f); err == nil || *attempts != 2 {
+			t.Fatalf("expected the last failure after 2 attempts, got %v after %d", err, *attempts)
+		}
+	})
+
+	t.Run("non json response", func(t *testing.T) {
+		if status := requestErrorStatus(fmt.Errorf("Response body is not json for status %d", http.StatusBadGateway)); status != http.StatusBadGateway {
+			t.Fatalf("expected the status of a response which is not JSON to be found, got %d", status)
+		}
+	})
+
+	t.Run("canceled request", func(t *testing.T) {
+		transport := newTestTransport(transportSettings{})
+		released := make(chan struct{})
+		defer close(released)
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		err := transport.call(ctx, true, func() error {
+			<-released
+			return nil
+		})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected the request to return once the context is done, got %v", err)
+		}
+	})
+
+	t.Run("stopped provider", func(t *testing.T) {
+		stop, cancel := context.WithCancel(context.Background())
+		transport := newTestTransport(transportSettings{stop: stop})
+		released := make(chan struct{})
+		defer close(released)
+		go cancel()
+		err := transport.call(context.Background(), true, func() error {
+			<-released
+			return nil
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected the request to return once the provider is stopped, got %v", err)
+		}
+		if err = transport.call(context.Background(), true, func() error { return nil }); !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected new requests to fail once the provider is stopped, got %v", err)
+		}
+	})
+}
+
+func TestRetryDelay(t *testing.T) {
+	withRetryBaseDelay(t, time.Second)
+
+	for _, tc := range []struct {
+		name       string
+		retryAfter string
+		attempt    int
+		expected   time.Duration
+	}{
+		{"first retry", "", 0, time.Second},
+		{"third retry", "", 2, 4 * time.Second},
+		{"capped", "", 10, retryMaxDelay},
+		{"retry after seconds", "120", 0, 120 * time.Second},
+		{"retry after past date", "Wed, 21 Oct 2015 07:28:00 GMT", 3, 0},
+		{"invalid retry after", "soon", 1, 2 * time.Second},
+	} {
+		resp := &http.Response{Header: http.Header{}}
+		if tc.retryAfter != "" {
+			resp.Header.Set("Retry-After", tc.retryAfter)
+		}
+		if delay := retryDelay(resp, tc.attempt); delay != tc.expected {
+			t.Errorf("%s: expected %s, got %s", tc.name, tc.expected, delay)
+		}
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := newRateLimiter(20)
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := limiter.wait(context.Background(), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The first request is immediate, the 4 others are spaced by 50ms
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Fatalf("expected the requests to be spaced by the rate limit, 5 requests took %s", elapsed)
+	}
+
+	if newRateLimiter(0) != nil {
+		t.Fatalf("expected no limiter for a rate of 0")
+	}
+	var unlimited *rateLimiter
+	if err := unlimited.wait(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,377 @@
+package gandi
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-gandi/go-gandi/livedns"
+)
+
+// defaultRecordTTL is the TTL used by LiveDNS when none is provided
+const defaultRecordTTL = 10800
+
+// zoneFileParser parses RFC 1035 zone files into LiveDNS records. It
+// supports the $ORIGIN and $TTL directives, comments, multi-line
+// records and the usual owner and TTL inheritance rules.
+type zoneFileParser struct {
+	zone       string
+	origin     string
+	defaultTTL int
+	lastOwner  string
+	lastTTL    int
+	records    map[string]*livedns.DomainRecord
+	order      []string
+}
+
+// parseZoneFile parses the content of a zone file of the given zone
+// and returns its records, merged by name and type. Owner names are
+// relative to the zone, the apex being '@'.
+func parseZoneFile(zone, content string) ([]livedns.DomainRecord, error) {
+	p := &zoneFileParser{
+		zone:    strings.TrimSuffix(zone, "."),
+		origin:  strings.TrimSuffix(zone, "."),
+		records: make(map[string]*livedns.DomainRecord),
+	}
+	lines, err := joinZoneFileLines(content)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range lines {
+		if err := p.parseLine(line); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line.number, err)
+		}
+	}
+	var records []livedns.DomainRecord
+	for _, key := range p.order {
+		records = append(records, *p.records[key])
+	}
+	return records, nil
+}
+
+type zoneFileLine struct {
+	number int
+	// indented is true when the line starts with a blank, meaning
+	// the owner is the one of the previous record
+	indented bool
+	tokens   []string
+}
+
+// joinZoneFileLines removes comments, splits lines into tokens and
+// joins the lines of records spanning several lines with parentheses.
+func joinZoneFileLines(content string) ([]zoneFileLine, error) {
+	var lines []zoneFileLine
+	var current *zoneFileLine
+	depth := 0
+	for i, raw := range strings.Split(content, "\n") {
+		tokens, opened, err := tokenizeZoneFileLine(raw)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if current == nil {
+			if len(tokens) == 0 {
+				continue
+			}
+			current = &zoneFileLine{
+				number:   i + 1,
+				indented: raw[0] == ' ' || raw[0] == '\t',
+			}
+		}
+		current.tokens = append(current.tokens, tokens...)
+		depth += opened
+		if depth < 0 {
+			return nil, fmt.Errorf("line %d: unbalanced parentheses", i+1)
+		}
+		if depth == 0 {
+			lines = append(lines, *current)
+			current = nil
+		}
+	}
+	if current != nil {
+		return nil, fmt.Errorf("line %d: unbalanced parentheses", current.number)
+	}
+	return lines, nil
+}
+
+// tokenizeZoneFileLine splits a line into tokens. Quoted strings are
+// kept with their quotes, comments are dropped and the returned int
+// is the number of opened minus closed parentheses.
+func tokenizeZoneFileLine(line string) (tokens []string, opened int, err error) {
+	var token strings.Builder
+	inQuotes := false
+	flush := func() {
+		if token.Len() > 0 {
+			tokens = append(tokens, token.String())
+			token.Reset()
+		}
+	}
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case inQuotes:
+			token.WriteByte(c)
+			if c == '\\' && i+1 < len(line) {
+				i++
+				token.WriteByte(line[i])
+			} else if c == '"' {
+				inQuotes = false
+			}
+		case c == '"':
+			token.WriteByte(c)
+			inQuotes = true
+		case c == ';':
+			flush()
+			return tokens, opened, nil
+		case c == '(':
+			flush()
+			opened++
+		case c == ')':
+			flush()
+			opened--
+		case c == ' ' || c == '\t' || c == '\r':
+			flush()
+		default:
+			token.WriteByte(c)
+		}
+	}
+	if inQuotes {
+		return nil, 0, fmt.Errorf("unterminated quoted string")
+	}
+	flush()
+	return tokens, opened, nil
+}
+
+func (p *zoneFileParser) parseLine(line zoneFileLine) error {
+	tokens := line.tokens
+	switch strings.ToUpper(tokens[0]) {
+	case "$ORIGIN":
+		if len(tokens) != 2 {
+			return fmt.Errorf("$ORIGIN expects a single domain name")
+		}
+		origin, err := p.absoluteName(tokens[1])
+		if err != nil {
+			return err
+		}
+		p.origin = origin
+		return nil
+	case "$TTL":
+		if len(tokens) != 2 {
+			return fmt.Errorf("$TTL expects a single TTL")
+		}
+		ttl, err := parseZoneFileTTL(tokens[1])
+		if err != nil {
+			return err
+		}
+		p.defaultTTL = ttl
+		return nil
+	case "$INCLUDE", "$GENERATE":
+		return fmt.Errorf("the %s directive is not supported", tokens[0])
+	}
+
+	owner := p.lastOwner
+	if !line.indented {
+		name, err := p.relativeName(tokens[0])
+		if err != nil {
+			return err
+		}
+		owner = name
+		tokens = tokens[1:]
+	}
+	if owner == "" {
+		return fmt.Errorf("the record has no owner name")
+	}
+	p.lastOwner = owner
+
+	ttl := 0
+	// The TTL and the class are both optional and can be in any order
+	for len(tokens) > 0 {
+		if strings.EqualFold(tokens[0], "IN") {
+			tokens = tokens[1:]
+		} else if t, err := parseZoneFileTTL(tokens[0]); err == nil {
+			ttl = t
+			tokens = tokens[1:]
+		} else {
+			break
+		}
+	}
+	if len(tokens) < 2 {
+		return fmt.Errorf("the record has no type or data")
+	}
+	recordType := strings.ToUpper(tokens[0])
+	value, err := p.recordValue(recordType, tokens[1:])
+	if err != nil {
+		return err
+	}
+
+	if ttl == 0 {
+		switch {
+		case p.defaultTTL != 0:
+			ttl = p.defaultTTL
+		case p.lastTTL != 0:
+			ttl = p.lastTTL
+		default:
+			ttl = defaultRecordTTL
+		}
+	}
+	p.lastTTL = ttl
+
+	key := owner + "/" + recordType
+	record, ok := p.records[key]
+	if !ok {
+		record = &livedns.DomainRecord{RrsetName: owner, RrsetType: recordType, RrsetTTL: ttl}
+		p.records[key] = record
+		p.order = append(p.order, key)
+	} else if record.RrsetTTL != ttl {
+		return fmt.Errorf("the records %s %s have different TTLs", owner, recordType)
+	}
+	if _, exists := containsRecord(record.RrsetValues, value); !exists {
+		record.RrsetValues = append(record.RrsetValues, value)
+	}
+	return nil
+}
+
+// rdataNameFields are the indexes of the domain names in the data of
+// the records, which are relative to the current origin
+var rdataNameFields = map[string]int{
+	"ALIAS": 0, "CNAME": 0, "DNAME": 0, "NS": 0, "PTR": 0, "MX": 1, "SRV": 3,
+}
+
+// recordValue returns the canonical value of a record from its fields.
+// The relative domain names of the data are resolved against the
+// current origin, since LiveDNS would resolve them against the zone.
+func (p *zoneFileParser) recordValue(recordType string, fields []string) (string, error) {
+	if isTXTRecordType(recordType) {
+		return joinTXTValue(strings.Join(wrapRecordsWithQuotes(fields), " ")), nil
+	}
+	fields = append([]string{}, fields...)
+	if i, ok := rdataNameFields[recordType]; ok && i < len(fields) && fields[i] != "." {
+		name, err := p.absoluteName(fields[i])
+		if err != nil {
+			return "", err
+		}
+		fields[i] = name + "."
+	}
+	value := strings.Join(fields, " ")
+	canonical, err := canonicalRecordValue(p.zone, recordType, value)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s record value %q: %w", recordType, value, err)
+	}
+	return canonical, nil
+}
+
+// absoluteName returns the fully qualified name, without its trailing
+// dot, of a name relative to the current origin.
+func (p *zoneFileParser) absoluteName(name string) (string, error) {
+	switch {
+	case name == "@":
+		return p.origin, nil
+	case strings.HasSuffix(name, "."):
+		return strings.ToLower(strings.TrimSuffix(name, ".")), nil
+	default:
+		return strings.ToLower(name + "." + p.origin), nil
+	}
+}
+
+// relativeName returns a name relative to the zone, as expected by
+// LiveDNS.
+func (p *zoneFileParser) relativeName(name string) (string, error) {
+	absolute, err := p.absoluteName(name)
+	if err != nil {
+		return "", err
+	}
+	zone := strings.ToLower(p.zone)
+	if absolute == zone {
+		return "@", nil
+	}
+	if !strings.HasSuffix(absolute, "."+zone) {
+		return "", fmt.Errorf("the name %s is out of the zone %s", name, p.zone)
+	}
+	return strings.TrimSuffix(absolute, "."+zone), nil
+}
+
+// parseZoneFileTTL parses a TTL, either in seconds or with BIND units
+// such as '1h30m'.
+func parseZoneFileTTL(s string) (int, error) {
+	if ttl, err := strconv.Atoi(s); err == nil && ttl >= 0 {
+		return ttl, nil
+	}
+	units := map[byte]int{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 604800}
+	ttl, number := 0, ""
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= '0' && c <= '9' {
+			number += string(c)
+			continue
+		}
+		unit, ok := units[c|0x20]
+		if !ok || number == "" {
+			return 0, fmt.Errorf("invalid TTL %q", s)
+		}
+		n, _ := strconv.Atoi(number)
+		ttl += n * unit
+		number = ""
+	}
+	if number != "" || s == "" {
+		return 0, fmt.Errorf("invalid TTL %q", s)
+	}
+	return ttl, nil
+}
+
+// isZoneFileManagedRecord returns true for records which are managed
+// by Gandi and then ignored in zone files: the SOA and the apex NS.
+func isZoneFileManagedRecord(record livedns.DomainRecord) bool {
+	return record.RrsetType == "SOA" || isDefaultRecord(record)
+}
+
+// renderZoneFile renders records as a zone file of the given zone.
+// Records are sorted, the apex first, so that the output only depends
+// on the records.
+func renderZoneFile(zone string, records []livedns.DomainRecord) string {
+	sorted := append([]livedns.DomainRecord{}, records...)
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.RrsetName != b.RrsetName {
+			if a.RrsetName == "@" || b.RrsetName == "@" {
+				return a.RrsetName == "@"
+			}
+			return a.RrsetName < b.RrsetName
+		}
+		return a.RrsetType < b.RrsetType
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "$ORIGIN %s.\n", strings.TrimSuffix(zone, "."))
+	for _, r := range sorted {
+		values := append([]string{}, r.RrsetValues...)
+		if isTXTRecordType(r.RrsetType) {
+			values = splitTXTValues(r.RrsetType, wrapRecordsWithQuotes(values))
+		}
+		sort.Strings(values)
+		for _, v := range values {
+			fmt.Fprintf(&b, "%s %d IN %s %s\n", r.RrsetName, r.RrsetTTL, r.RrsetType, v)
+		}
+	}
+	return b.String()
+}
+
+// normalizeZoneFile parses a zone file and renders it back, without
+// the records managed by Gandi, so that two equivalent zone files
+// have the same normalized form.
+func normalizeZoneFile(zone, content string) (string, error) {
+	records, err := parseZoneFile(zone, content)
+	if err != nil {
+		return "", err
+	}
+	return renderZoneFile(zone, withoutZoneFileManagedRecords(records)), nil
+}
+
+func withoutZoneFileManagedRecords(records []livedns.DomainRecord) []livedns.DomainRecord {
+	var filtered []livedns.DomainRecord
+	for _, r := range records {
+		if !isZoneFileManagedRecord(r) {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
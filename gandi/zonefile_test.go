@@ -0,0 +1,94 @@
+package gandi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-gandi/go-gandi/livedns"
+)
+
+func TestParseZoneFile(t *testing.T) {
+	content := `$ORIGIN example.com.
+$TTL 1h
+; The SOA is managed by Gandi
+@	IN SOA ns1.gandi.net. hostmaster.gandi.net. (
+		1 10800 3600 604800 10800 ) ; serial and timers
+@	300 IN A 192.168.0.1
+	300 IN TXT "v=spf1 -all"
+www.example.com. IN 600 CNAME example.com.
+mail	MX 10 mx1
+	MX 20 mx2
+$ORIGIN sub.example.com.
+api	2d A 192.168.0.2
+www	300 CNAME foo
+@	300 MX 10 Mail
+v6	300 AAAA 2001:DB8:0:0::1
+_sip._tcp	300 SRV 10 0 5060 sip
+`
+	records, err := parseZoneFile("example.com", content)
+	if err != nil {
+		t.Fatalf("failed to parse the zone file: %s", err)
+	}
+	expected := []livedns.DomainRecord{
+		{RrsetName: "@", RrsetType: "SOA", RrsetTTL: 3600, RrsetValues: []string{"ns1.gandi.net. hostmaster.gandi.net. 1 10800 3600 604800 10800"}},
+		{RrsetName: "@", RrsetType: "A", RrsetTTL: 300, RrsetValues: []string{"192.168.0.1"}},
+		{RrsetName: "@", RrsetType: "TXT", RrsetTTL: 300, RrsetValues: []string{`"v=spf1 -all"`}},
+		{RrsetName: "www", RrsetType: "CNAME", RrsetTTL: 600, RrsetValues: []string{"example.com."}},
+		{RrsetName: "mail", RrsetType: "MX", RrsetTTL: 3600, RrsetValues: []string{"10 mx1.example.com.", "20 mx2.example.com."}},
+		{RrsetName: "api.sub", RrsetType: "A", RrsetTTL: 172800, RrsetValues: []string{"192.168.0.2"}},
+		// The names of the data are relative to the current origin
+		{RrsetName: "www.sub", RrsetType: "CNAME", RrsetTTL: 300, RrsetValues: []string{"foo.sub.example.com."}},
+		{RrsetName: "sub", RrsetType: "MX", RrsetTTL: 300, RrsetValues: []string{"10 mail.sub.example.com."}},
+		{RrsetName: "v6.sub", RrsetType: "AAAA", RrsetTTL: 300, RrsetValues: []string{"2001:db8::1"}},
+		{RrsetName: "_sip._tcp.sub", RrsetType: "SRV", RrsetTTL: 300, RrsetValues: []string{"10 0 5060 sip.sub.example.com."}},
+	}
+	if !reflect.DeepEqual(records, expected) {
+		t.Fatalf("expected %v, got %v", expected, records)
+	}
+}
+
+func TestParseZoneFile_errors(t *testing.T) {
+	for name, content := range map[string]string{
+		"out of zone":        "www.example.org. 300 IN A 192.168.0.1",
+		"unbalanced":         "@ 300 IN SOA ( ns1 hostmaster 1 2 3 4 5",
+		"unterminated quote": `@ 300 IN TXT "v=spf1`,
+		"missing data":       "www 300 IN A",
+		"different TTLs":     "www 300 IN A 192.168.0.1\nwww 600 IN A 192.168.0.2",
+		"include":            "$INCLUDE other.zone",
+		"invalid value":      "www 300 IN A 2001:db8::1",
+	} {
+		if _, err := parseZoneFile("example.com", content); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestNormalizeZoneFile(t *testing.T) {
+	a := `@ 300 IN NS ns1.gandi.net.
+www 300 IN A 192.168.0.2
+www 300 IN A 192.168.0.1
+@ 300 IN TXT v=spf1
+`
+	b := `$ORIGIN example.com.
+$TTL 300
+example.com. TXT "v=spf1"
+www A 192.168.0.1
+    A 192.168.0.2
+`
+	normalizedA, err := normalizeZoneFile("example.com", a)
+	if err != nil {
+		t.Fatalf("failed to normalize: %s", err)
+	}
+	normalizedB, err := normalizeZoneFile("example.com", b)
+	if err != nil {
+		t.Fatalf("failed to normalize: %s", err)
+	}
+	expected := `$ORIGIN example.com.
+@ 300 IN TXT "v=spf1"
+www 300 IN A 192.168.0.1
+www 300 IN A 192.168.0.2
+`
+	if normalizedA != expected || normalizedB != expected {
+		t.Fatalf("expected %q, got %q and %q", expected, normalizedA, normalizedB)
+	}
+}
@@ -1,15 +1,82 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
 	"github.com/go-gandi/terraform-provider-gandi/v2/gandi"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "generate-imports" {
+		os.Exit(generateImports(os.Args[2:]))
+	}
+
 	plugin.Serve(&plugin.ServeOpts{
 		ProviderFunc: func() *schema.Provider {
 			return gandi.Provider()
 		},
 	})
 }
+
+// generateImports writes the configuration and the import blocks of
+// the resources of the Gandi account, to onboard it into Terraform
+func generateImports(args []string) int {
+	flags := flag.NewFlagSet("generate-imports", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), `Usage: %s generate-imports [options]
+
+Writes the configuration of the domains, LiveDNS records, glue records,
+DNSSEC keys, mailboxes, email forwards and SimpleHosting instances of
+the Gandi account, along with the import blocks importing them with
+Terraform >= 1.5. The Gandi API is configured by the environment
+variables of the provider, such as GANDI_PERSONAL_ACCESS_TOKEN.
+
+Options:
+`, os.Args[0])
+		flags.PrintDefaults()
+	}
+	output := flags.String("output", "-", "The file the configuration is written to, or - for the standard output")
+	domains := flags.String("domains", "", "A comma separated list of the domains to generate, all of them by default. The SimpleHosting instances are only generated along with all the domains.")
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+
+	var options gandi.ImportConfigOptions
+	for _, fqdn := range strings.Split(*domains, ",") {
+		if fqdn = strings.TrimSpace(fqdn); fqdn != "" {
+			options.Domains = append(options.Domains, fqdn)
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	var f *os.File
+	if *output != "-" {
+		var err error
+		if f, err = os.Create(*output); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		w = f
+	}
+	err := gandi.GenerateImportConfig(context.Background(), w, options)
+	if f != nil {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}